@@ -2,9 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"net/http"
-	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
@@ -14,10 +14,15 @@ import (
 	"github.com/Flafl/DevOpsCore/config"
 	"github.com/Flafl/DevOpsCore/db"
 	auth "github.com/Flafl/DevOpsCore/internal/Auth"
+	"github.com/Flafl/DevOpsCore/internal/credentials"
+	"github.com/Flafl/DevOpsCore/internal/events"
+	"github.com/Flafl/DevOpsCore/internal/extractor"
 	"github.com/Flafl/DevOpsCore/internal/handlers"
+	"github.com/Flafl/DevOpsCore/internal/models"
 	"github.com/Flafl/DevOpsCore/internal/repository"
 	"github.com/Flafl/DevOpsCore/internal/router"
 	"github.com/Flafl/DevOpsCore/internal/scheduler"
+	"github.com/Flafl/DevOpsCore/internal/shell"
 	websocket "github.com/Flafl/DevOpsCore/internal/webSocket"
 	"github.com/gin-gonic/gin"
 )
@@ -25,6 +30,46 @@ import (
 func main() {
 	cfg := config.Load()
 
+	// ctx is cancelled on SIGINT/SIGTERM, which reaches every in-flight OLT
+	// session through the scheduler.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shell.SetDefaultCommandTimeout(cfg.CommandTimeout)
+	shell.SetCommandTimeout("info configure", cfg.BackupCommandTimeout)
+	shell.SetCommandTimeout("display current-configuration", cfg.BackupCommandTimeout)
+	shell.SetCommandTimeout("show running-config", cfg.BackupCommandTimeout)
+	shell.SetRetryPolicy(shell.RetryPolicy{
+		Attempts:  cfg.SSHRetries,
+		BaseDelay: cfg.SSHRetryBase,
+		MaxDelay:  cfg.SSHRetryMax,
+		Jitter:    0.2,
+	})
+	shell.SetSessionLimits(cfg.SSHMaxSessions, cfg.SSHMaxSessionsPerOLT)
+	shell.SetBreakerConfig(shell.BreakerConfig{
+		Threshold:     cfg.BreakerThreshold,
+		ProbeInterval: cfg.BreakerProbeInterval,
+	})
+	if cfg.SessionProfiles != "" {
+		profiles, err := shell.LoadSessionProfiles(cfg.SessionProfiles)
+		if err != nil {
+			log.Fatalf("session profiles: %v", err)
+		}
+		shell.SetSessionProfiles(profiles)
+	}
+	if cfg.TextFSMDir != "" {
+		templates, err := extractor.LoadTemplates(cfg.TextFSMDir)
+		if err != nil {
+			log.Fatalf("textfsm templates: %v", err)
+		}
+		extractor.SetTemplates(templates)
+	}
+	shell.SetRecordDir(cfg.SSHRecordDir)
+	if cfg.SSHReplayDir != "" {
+		log.Printf("WARN: replaying captures from %s, no OLT will be contacted", cfg.SSHReplayDir)
+		shell.SetReplayDir(cfg.SSHReplayDir)
+	}
+
 	database := db.Connect(cfg)
 
 	powerRepo := repository.NewPowerRepository(database)
@@ -33,6 +78,28 @@ func main() {
 	portRepo := repository.NewPortProtectionRepo(database)
 	backupRepo := repository.NewBackupRepository(database)
 	userRepo := repository.NewUserRepository(database)
+	oltRepo := repository.NewOltRepository(database)
+
+	credRepo := repository.NewCredentialRepository(database)
+	hostKeyRepo := repository.NewHostKeyRepository(database)
+	jumpRepo := repository.NewJumpRepository(database)
+	reportRepo := repository.NewJobReportRepository(database)
+	eventRepo := repository.NewOltEventRepository(database)
+	ontStatusRepo := repository.NewOntStatusRepository(database)
+	ontSerialRepo := repository.NewOntSerialRepository(database)
+
+	shell.SetInventory(oltRepo)
+
+	credCipher, err := credentials.NewCipher(cfg.CredentialsMasterKey)
+	if err != nil {
+		log.Fatalf("credentials: %v", err)
+	}
+	if credCipher == nil {
+		log.Println("WARN: CREDENTIALS_MASTER_KEY not set, using OLT_SSH_USER/OLT_SSH_PASS for every OLT")
+	}
+	credStore := credentials.NewStore(credRepo, jumpRepo, credCipher)
+	shell.SetCredentialResolver(credStore)
+	shell.SetJumpResolver(credStore)
 
 	jwtManager := auth.NewJWTManager(auth.JWTconfig{
 		SecretKey:            []byte(cfg.JWTSecret),
@@ -44,8 +111,44 @@ func main() {
 	hub := websocket.NewHub()
 	go hub.Run()
 
-	sched := scheduler.New(cfg, hub, powerRepo, descRepo, healthRepo, portRepo, backupRepo)
-	sched.Start()
+	shell.SetHostKeyStore(hostKeyRepo, func(k models.HostKey) {
+		log.Printf("SECURITY: host key mismatch for %s: pinned %s, presented %s", k.Host, k.Fingerprint, k.PendingFingerprint)
+		msg, _ := json.Marshal(map[string]string{
+			"type":      "hostkey_mismatch",
+			"host":      k.Host,
+			"pinned":    k.Fingerprint,
+			"presented": k.PendingFingerprint,
+		})
+		hub.Broadcast(msg)
+	})
+
+	receiver := events.NewReceiver(oltRepo, eventRepo, func(e models.OltEvent) {
+		msg, _ := json.Marshal(map[string]any{
+			"type":  "olt_event",
+			"event": e,
+		})
+		hub.Broadcast(msg)
+	})
+	if cfg.TrapMapFile != "" {
+		if err := receiver.LoadTrapMap(cfg.TrapMapFile); err != nil {
+			log.Fatalf("trap map: %v", err)
+		}
+	}
+	if cfg.TrapListenAddr != "" {
+		if err := receiver.ListenTraps(ctx, cfg.TrapListenAddr, cfg.TrapCommunity); err != nil {
+			log.Fatalf("trap receiver: %v", err)
+		}
+		log.Printf("trap receiver listening on %s", cfg.TrapListenAddr)
+	}
+	if cfg.SyslogListenAddr != "" {
+		if err := receiver.ListenSyslog(ctx, cfg.SyslogListenAddr); err != nil {
+			log.Fatalf("syslog receiver: %v", err)
+		}
+		log.Printf("syslog receiver listening on %s", cfg.SyslogListenAddr)
+	}
+
+	sched := scheduler.New(cfg, hub, powerRepo, descRepo, ontStatusRepo, ontSerialRepo, healthRepo, portRepo, backupRepo, oltRepo, reportRepo)
+	sched.Start(ctx)
 
 	server := gin.Default()
 
@@ -59,11 +162,20 @@ func main() {
 	portH := handlers.NewPortHandler(portRepo)
 	backupH := handlers.NewBackupHandler(backupRepo)
 	userH := handlers.NewUserHandler(userRepo)
+	oltH := handlers.NewOltHandler(oltRepo, cfg.OLTsAPIURL)
+	credH := handlers.NewCredentialHandler(credRepo, credStore)
+	hostKeyH := handlers.NewHostKeyHandler(hostKeyRepo)
+	jumpH := handlers.NewJumpHandler(jumpRepo, credStore)
+	reportH := handlers.NewJobReportHandler(reportRepo)
+	eventH := handlers.NewEventHandler(eventRepo)
+	ontH := handlers.NewOntStatusHandler(ontStatusRepo)
+	configH := handlers.NewConfigHandler(backupRepo)
+	serialH := handlers.NewOntSerialHandler(ontSerialRepo)
 	authH := handlers.NewAuthHandler(userRepo, jwtManager)
 
 	pageH := handlers.NewPageHandler(filepath.Join(projectRoot, "templates"))
 
-	router.Setup(server, jwtManager, hub, powerH, descH, healthH, portH, backupH, userH, authH, pageH)
+	router.Setup(server, jwtManager, hub, powerH, descH, healthH, portH, backupH, userH, oltH, credH, hostKeyH, jumpH, reportH, eventH, ontH, configH, serialH, authH, pageH)
 
 	// Graceful shutdown
 	srv := &http.Server{
@@ -76,16 +188,15 @@ func main() {
 			log.Fatalf("server failed: %v", err)
 		}
 	}()
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	<-ctx.Done()
 
 	log.Println("shutdown signal received, stopping...")
+	sched.Stop()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
+	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Fatalf("server forced to shutdown: %v", err)
 	}
 
@@ -0,0 +1,86 @@
+// Command olt-sim runs one or more fake Nokia ISAM OLTs over SSH, answering
+// from the simulator fixtures, optionally each with an SNMP agent. Point inventory rows at 127.0.0.1:<port> to
+// exercise the scheduler without real devices.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/Flafl/DevOpsCore/internal/simulator"
+	"golang.org/x/crypto/ssh"
+)
+
+func main() {
+	var cfg simulator.Config
+	host := flag.String("host", "127.0.0.1", "address to listen on")
+	port := flag.Int("port", 2201, "first port; each further OLT uses the next one")
+	count := flag.Int("count", 1, "number of simulated OLTs")
+	flag.StringVar(&cfg.User, "user", "", "required username (empty accepts any)")
+	flag.StringVar(&cfg.Password, "pass", "", "required password")
+	flag.StringVar(&cfg.FixtureDir, "fixtures", "", "directory overriding the built-in fixtures")
+	flag.StringVar(&cfg.BackupFile, "backup", "", "file answering \"info configure flat\"")
+	flag.DurationVar(&cfg.Delay, "delay", 0, "delay before every command output")
+	flag.Float64Var(&cfg.DisconnectRate, "disconnect-rate", 0, "probability a command drops the connection")
+	flag.Float64Var(&cfg.GarbageRate, "garbage-rate", 0, "probability a command returns garbage")
+	snmpPort := flag.Int("snmp-port", 0, "first UDP port of the SNMP agents; 0 disables them")
+	community := flag.String("community", "public", "SNMP community the agents accept")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var servers []*simulator.Server
+	var agents []*simulator.SNMPAgent
+	for i := 0; i < *count; i++ {
+		c := cfg
+		c.Hostname = fmt.Sprintf("sim-olt-%02d", i+1)
+		srv, err := simulator.New(c)
+		if err != nil {
+			log.Fatalf("olt-sim: %v", err)
+		}
+		addr := net.JoinHostPort(*host, strconv.Itoa(*port+i))
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			log.Fatalf("olt-sim: %v", err)
+		}
+		log.Printf("olt-sim: %s on %s (%s)", c.Hostname, addr, ssh.FingerprintSHA256(srv.HostKey()))
+		go func() {
+			if err := srv.Serve(ln); err != nil {
+				log.Printf("olt-sim: %s: %v", addr, err)
+			}
+		}()
+		servers = append(servers, srv)
+
+		if *snmpPort == 0 {
+			continue
+		}
+		agent := simulator.NewSNMPAgent(*community)
+		snmpAddr := net.JoinHostPort(*host, strconv.Itoa(*snmpPort+i))
+		pc, err := net.ListenPacket("udp", snmpAddr)
+		if err != nil {
+			log.Fatalf("olt-sim: %v", err)
+		}
+		log.Printf("olt-sim: %s SNMP agent on %s", c.Hostname, snmpAddr)
+		go func() {
+			if err := agent.Serve(pc); err != nil {
+				log.Printf("olt-sim: %s: %v", snmpAddr, err)
+			}
+		}()
+		agents = append(agents, agent)
+	}
+
+	<-ctx.Done()
+	for _, srv := range servers {
+		_ = srv.Close()
+	}
+	for _, a := range agents {
+		_ = a.Close()
+	}
+}
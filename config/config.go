@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -22,11 +23,83 @@ type Config struct {
 	OLTUser string
 	OLTPass string
 
+	// CredentialsMasterKey encrypts the per-OLT and per-site credential
+	// sets. OLTUser/OLTPass remain the fallback for OLTs without a set.
+	CredentialsMasterKey string
+
+	// OLTsAPIURL is the optional external device list used to sync the
+	// local OLT inventory. Leave empty to manage the inventory by hand.
+	OLTsAPIURL      string
+	OLTSyncInterval time.Duration
+
+	// ScanTimeout bounds a whole scan job; CommandTimeout and
+	// BackupCommandTimeout bound single commands on one device.
+	ScanTimeout          time.Duration
+	CommandTimeout       time.Duration
+	BackupCommandTimeout time.Duration
+
+	// SSHRetries is the number of attempts per device and scan; the delay
+	// between attempts doubles from SSHRetryBase up to SSHRetryMax.
+	SSHRetries   int
+	SSHRetryBase time.Duration
+	SSHRetryMax  time.Duration
+
+	// SSHMaxSessions caps concurrent sessions across all jobs;
+	// SSHMaxSessionsPerOLT caps them per device.
+	SSHMaxSessions       int
+	SSHMaxSessionsPerOLT int
+
+	// A device is marked unreachable after BreakerThreshold consecutive
+	// failed scans and then only probed every BreakerProbeInterval.
+	BreakerThreshold     int
+	BreakerProbeInterval time.Duration
+
+	// SSHRecordDir captures every command and its output per device;
+	// SSHReplayDir serves such captures instead of connecting to devices.
+	SSHRecordDir string
+	SSHReplayDir string
+
+	// SessionProfiles is an optional JSON file of per-vendor and per-model
+	// commands sent after login, replacing the drivers' defaults.
+	SessionProfiles string
+
+	// TextFSMDir holds TextFSM templates by vendor, firmware and command,
+	// tried before the built-in parsers. Empty leaves them off.
+	TextFSMDir string
+
+	// SNMP* is the account used to poll the health of OLTs whose
+	// health_source is "snmp". SNMPVersion is "2c" or "3"; the v3 fields
+	// are ignored for 2c.
+	SNMPVersion   string
+	SNMPCommunity string
+	SNMPPort      int
+	SNMPTimeout   time.Duration
+	SNMPRetries   int
+	SNMPUser      string
+	SNMPAuthProto string
+	SNMPAuthPass  string
+	SNMPPrivProto string
+	SNMPPrivPass  string
+
+	// TrapListenAddr and SyslogListenAddr enable the UDP receivers for OLT
+	// alarms, e.g. ":162" and ":514". Empty leaves them off.
+	TrapListenAddr   string
+	TrapCommunity    string
+	SyslogListenAddr string
+	// TrapMapFile names the trap OIDs and alarm-type values of the OLTs'
+	// MIBs, see events.LoadTrapMap.
+	TrapMapFile string
+
 	PowerScanInterval  time.Duration
 	HealthScanInterval time.Duration
 	DescScanInterval   time.Duration
 	PortScanInterval   time.Duration
 	BackupInterval     time.Duration
+
+	// CollectionPlan replaces the power, description, health and port scans
+	// with one job that collects everything due from a device in a single
+	// session, ticking at the shortest of their intervals.
+	CollectionPlan bool
 }
 
 func Load() *Config {
@@ -51,11 +124,55 @@ func Load() *Config {
 		OLTUser: getEnv("OLT_SSH_USER", ""),
 		OLTPass: getEnv("OLT_SSH_PASS", ""),
 
+		CredentialsMasterKey: getEnv("CREDENTIALS_MASTER_KEY", ""),
+
+		OLTsAPIURL:      getEnv("OLTS_API_ENV", ""),
+		OLTSyncInterval: parseDuration(getEnv("OLT_SYNC_INTERVAL", "0")),
+
+		ScanTimeout:          parseDuration(getEnv("SCAN_TIMEOUT", "2h")),
+		CommandTimeout:       parseDuration(getEnv("SSH_COMMAND_TIMEOUT", "2m")),
+		BackupCommandTimeout: parseDuration(getEnv("SSH_BACKUP_TIMEOUT", "30m")),
+
+		SSHRetries:   parseInt(getEnv("SSH_RETRIES", "3")),
+		SSHRetryBase: parseDuration(getEnv("SSH_RETRY_BASE", "5s")),
+		SSHRetryMax:  parseDuration(getEnv("SSH_RETRY_MAX", "1m")),
+
+		SSHMaxSessions:       parseInt(getEnv("SSH_MAX_SESSIONS", "33")),
+		SSHMaxSessionsPerOLT: parseInt(getEnv("SSH_MAX_SESSIONS_PER_OLT", "1")),
+
+		BreakerThreshold:     parseInt(getEnv("BREAKER_THRESHOLD", "3")),
+		BreakerProbeInterval: parseDuration(getEnv("BREAKER_PROBE_INTERVAL", "1h")),
+
+		SSHRecordDir: getEnv("SSH_RECORD_DIR", ""),
+		SSHReplayDir: getEnv("SSH_REPLAY_DIR", ""),
+
+		SessionProfiles: getEnv("SESSION_PROFILES", ""),
+
+		TextFSMDir: getEnv("TEXTFSM_DIR", ""),
+
+		SNMPVersion:   getEnv("SNMP_VERSION", "2c"),
+		SNMPCommunity: getEnv("SNMP_COMMUNITY", "public"),
+		SNMPPort:      parseInt(getEnv("SNMP_PORT", "161")),
+		SNMPTimeout:   parseDuration(getEnv("SNMP_TIMEOUT", "5s")),
+		SNMPRetries:   parseInt(getEnv("SNMP_RETRIES", "2")),
+		SNMPUser:      getEnv("SNMP_V3_USER", ""),
+		SNMPAuthProto: getEnv("SNMP_V3_AUTH_PROTO", "SHA"),
+		SNMPAuthPass:  getEnv("SNMP_V3_AUTH_PASS", ""),
+		SNMPPrivProto: getEnv("SNMP_V3_PRIV_PROTO", "AES"),
+		SNMPPrivPass:  getEnv("SNMP_V3_PRIV_PASS", ""),
+
+		TrapListenAddr:   getEnv("TRAP_LISTEN_ADDR", ""),
+		TrapCommunity:    getEnv("TRAP_COMMUNITY", ""),
+		SyslogListenAddr: getEnv("SYSLOG_LISTEN_ADDR", ""),
+		TrapMapFile:      getEnv("TRAP_MAP_FILE", ""),
+
 		PowerScanInterval:  parseDuration(getEnv("POWER_SCAN_INTERVAL", "6h")),
 		HealthScanInterval: parseDuration(getEnv("HEALTH_SCAN_INTERVAL", "0.5h")),
 		DescScanInterval:   parseDuration(getEnv("DESC_SCAN_INTERVAL", "6h")),
 		PortScanInterval:   parseDuration(getEnv("PORT_SCAN_INTERVAL", "0.5h")),
 		BackupInterval:     parseDuration(getEnv("BACKUP_INTERVAL", "24h")),
+
+		CollectionPlan: parseBool(getEnv("COLLECTION_PLAN", "false")),
 	}
 }
 
@@ -82,3 +199,19 @@ func parseDuration(s string) time.Duration {
 	}
 	return d
 }
+
+func parseInt(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func parseBool(s string) bool {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false
+	}
+	return b
+}
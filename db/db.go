@@ -24,6 +24,19 @@ func Connect(cfg *config.Config) *gorm.DB {
 	sqlDB.SetConnMaxIdleTime(5)
 	sqlDB.SetConnMaxLifetime(5 * time.Minute)
 
+	// ONT states used to be soft-deleted on every scan; purge those rows so
+	// the unique (host, ont_idx) index can be built.
+	if db.Migrator().HasTable(&models.OntStatus{}) {
+		if err := db.Exec("DELETE FROM ont_statuses WHERE deleted_at IS NOT NULL").Error; err != nil {
+			log.Fatalf("failed to purge deleted ont statuses: %v", err)
+		}
+	}
+
+	// Power readings without ont_rx come from the parser that took the
+	// first optics column, the ONT's Rx level, as OltRx; flag them below.
+	legacyPower := db.Migrator().HasTable(&models.PowerReading{}) &&
+		!db.Migrator().HasColumn(&models.PowerReading{}, "OntRx")
+
 	if err := db.AutoMigrate(
 		&models.PowerReading{},
 		&models.OntDescription{},
@@ -31,10 +44,27 @@ func Connect(cfg *config.Config) *gorm.DB {
 		&models.PortProtectionRecord{},
 		&models.OltBackups{},
 		&models.User{},
+		&models.Olt{},
+		&models.CredentialSet{},
+		&models.HostKey{},
+		&models.JumpHost{},
+		&models.JumpRoute{},
+		&models.JobReport{},
+		&models.OltEvent{},
+		&models.OntStatus{},
+		&models.OntSerial{},
 	); err != nil {
 		log.Fatalf("failed to migrate database: %v", err)
 	}
 
+	if legacyPower {
+		err := db.Exec(`UPDATE power_readings SET ont_rx = olt_rx, legacy_olt_rx = true
+			WHERE host NOT IN (SELECT host FROM olts WHERE vendor IN ('huawei', 'zte'))`).Error
+		if err != nil {
+			log.Fatalf("failed to flag legacy power readings: %v", err)
+		}
+	}
+
 	log.Println("database connected and migrated")
 	return db
 }
@@ -0,0 +1,71 @@
+// Package credentials keeps per-OLT, per-site and default SSH accounts with
+// their passwords encrypted at rest.
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// ErrNoMasterKey is returned when secrets are stored or read without a
+// master key configured.
+var ErrNoMasterKey = errors.New("credentials: master key not configured")
+
+// Cipher seals secrets with AES-256-GCM under a key derived from the master
+// key. Sealed values are base64(nonce || ciphertext).
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher derives the encryption key from masterKey. An empty master key
+// yields a nil Cipher, on which every operation returns ErrNoMasterKey.
+func NewCipher(masterKey string) (*Cipher, error) {
+	if masterKey == "" {
+		return nil, nil
+	}
+	key := sha256.Sum256([]byte(masterKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+func (c *Cipher) Encrypt(plain string) (string, error) {
+	if c == nil {
+		return "", ErrNoMasterKey
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *Cipher) Decrypt(sealed string) (string, error) {
+	if c == nil {
+		return "", ErrNoMasterKey
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	n := c.aead.NonceSize()
+	if len(raw) < n {
+		return "", errors.New("credentials: sealed secret too short")
+	}
+	plain, err := c.aead.Open(nil, raw[:n], raw[n:], nil)
+	if err != nil {
+		return "", errors.New("credentials: cannot decrypt secret, wrong master key?")
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,101 @@
+package credentials
+
+import (
+	"fmt"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/Flafl/DevOpsCore/internal/shell"
+)
+
+// Store resolves the account and the jump-host chain to use for an OLT from
+// the database.
+type Store struct {
+	repo   repository.CredentialRepository
+	jumps  repository.JumpRepository
+	cipher *Cipher
+}
+
+func NewStore(r repository.CredentialRepository, jr repository.JumpRepository, c *Cipher) *Store {
+	return &Store{repo: r, jumps: jr, cipher: c}
+}
+
+// Seal encrypts a password for storage in a CredentialSet.
+func (s *Store) Seal(password string) (string, error) {
+	return s.cipher.Encrypt(password)
+}
+
+// Resolve returns the account for the OLT at host on site, preferring an OLT
+// set over a site set over the default. An empty username with a nil error
+// means no set applies and the caller should use its own fallback.
+func (s *Store) Resolve(host, site string) (username, password string, err error) {
+	sets, err := s.repo.GetForOLT(host, site)
+	if err != nil {
+		return "", "", err
+	}
+
+	var best *models.CredentialSet
+	for i := range sets {
+		if best == nil || rank(sets[i].Scope) < rank(best.Scope) {
+			best = &sets[i]
+		}
+	}
+	if best == nil {
+		return "", "", nil
+	}
+
+	password, err = s.cipher.Decrypt(best.SecretEnc)
+	if err != nil {
+		return "", "", fmt.Errorf("credential set %q: %w", best.Name, err)
+	}
+	return best.Username, password, nil
+}
+
+func rank(scope string) int {
+	switch scope {
+	case models.CredentialScopeOLT:
+		return 0
+	case models.CredentialScopeSite:
+		return 1
+	}
+	return 2
+}
+
+// Route returns the jump hosts to dial, in order, to reach the OLT at host
+// on site. An OLT route wins over the site route.
+func (s *Store) Route(host, site string) ([]shell.JumpHop, error) {
+	routes, err := s.jumps.GetRoutesForOLT(host, site)
+	if err != nil {
+		return nil, err
+	}
+
+	var route *models.JumpRoute
+	for i := range routes {
+		if route == nil || rank(routes[i].Scope) < rank(route.Scope) {
+			route = &routes[i]
+		}
+	}
+	if route == nil {
+		return nil, nil
+	}
+
+	names := route.HopNames()
+	hops := make([]shell.JumpHop, 0, len(names))
+	for _, name := range names {
+		jh, err := s.jumps.GetHostByName(name)
+		if err != nil {
+			return nil, fmt.Errorf("jump host %q: %w", name, err)
+		}
+		pass, err := s.cipher.Decrypt(jh.SecretEnc)
+		if err != nil {
+			return nil, fmt.Errorf("jump host %q: %w", name, err)
+		}
+		hops = append(hops, shell.JumpHop{
+			Name:     jh.Name,
+			Addr:     jh.Host,
+			Username: jh.Username,
+			Password: pass,
+		})
+	}
+	return hops, nil
+}
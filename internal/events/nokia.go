@@ -0,0 +1,66 @@
+package events
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Event kinds.
+const (
+	KindLOS              = "los"
+	KindDyingGasp        = "dying_gasp"
+	KindBoardDown        = "board_down"
+	KindProtectionSwitch = "protection_switch"
+)
+
+// alarm is what classify makes of one message.
+type alarm struct {
+	kind     string
+	object   string
+	severity string
+	cleared  bool
+}
+
+// nokiaAlarms maps ISAM alarm wording to event kinds, most specific first.
+// The same wording shows up in the alarm log, the syslog forwarding of it
+// and the string varbinds of the alarm traps.
+var nokiaAlarms = []struct {
+	kind string
+	re   *regexp.Regexp
+}{
+	{KindDyingGasp, regexp.MustCompile(`(?i)dying[- ]?gasp|\bdg\b`)},
+	{KindProtectionSwitch, regexp.MustCompile(`(?i)(?:prot(?:ection)?[- ]?(?:group[- ]?)?)?switch[- ]?over|\bswo\b|protection[- ]switch`)},
+	{KindBoardDown, regexp.MustCompile(`(?i)board[- ]?(?:down|missing|fail(?:ure)?|reset|init)|(?:lt|nt)[- ]?(?:board[- ]?)?(?:down|fail(?:ure)?)|shub[- ]?(?:down|fail)`)},
+	{KindLOS, regexp.MustCompile(`(?i)\blos\b|loss[- ]of[- ]signal`)},
+}
+
+var (
+	// an ONT (1/1/1/1/1), PON port (1/1/1/1) or slot (1/1/1), optionally
+	// behind an ISAM prefix such as "ont:" or "lt:"
+	reAlarmObject = regexp.MustCompile(`(?i)(?:\b(?:ont|pon|lt|nt|slot)[:\- ]?)?\b(\d+(?:/\d+){2,4})\b`)
+	reAlarmClear  = regexp.MustCompile(`(?i)\bclear(?:ed)?\b|alarm[- ]end|\bend of alarm\b`)
+	reAlarmSev    = regexp.MustCompile(`(?i)\b(critical|major|minor|warning|indeterminate)\b`)
+)
+
+// classify recognises a Nokia alarm in text. Messages that are not one of
+// the tracked kinds are reported as not ok.
+func classify(text string) (alarm, bool) {
+	var a alarm
+	for _, na := range nokiaAlarms {
+		if na.re.MatchString(text) {
+			a.kind = na.kind
+			break
+		}
+	}
+	if a.kind == "" {
+		return alarm{}, false
+	}
+	if m := reAlarmObject.FindStringSubmatch(text); m != nil {
+		a.object = m[1]
+	}
+	if m := reAlarmSev.FindStringSubmatch(text); m != nil {
+		a.severity = strings.ToLower(m[1])
+	}
+	a.cleared = reAlarmClear.MatchString(text)
+	return a, true
+}
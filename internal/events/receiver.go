@@ -0,0 +1,157 @@
+// Package events receives the alarms OLTs push on their own, SNMP traps and
+// syslog messages, and turns the Nokia ones into stored OltEvents.
+package events
+
+import (
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+)
+
+// Inventory resolves event sources to OLTs. repository.OltRepository
+// satisfies it.
+type Inventory interface {
+	GetEnabled() ([]models.Olt, error)
+}
+
+// Store persists events. repository.OltEventRepository satisfies it.
+type Store interface {
+	Create(e *models.OltEvent) error
+}
+
+// inventoryTTL is how long the source address map is reused before the
+// inventory is read again.
+const inventoryTTL = time.Minute
+
+// Unknown sources are logged once per unknownTTL. At most maxUnknown are
+// remembered, so spoofed addresses cannot grow the set without bound.
+const (
+	unknownTTL = time.Hour
+	maxUnknown = 1024
+)
+
+// Receiver maps incoming messages to inventory OLTs, classifies them and
+// hands every recognised alarm to the store and to notify.
+type Receiver struct {
+	inv    Inventory
+	store  Store
+	notify func(models.OltEvent)
+	traps  *TrapMap
+
+	mu       sync.Mutex
+	olts     map[string]models.Olt // by source IP
+	loadedAt time.Time
+	unknown  map[string]time.Time // last logged, by source IP
+}
+
+// NewReceiver builds a receiver. notify may be nil.
+func NewReceiver(inv Inventory, store Store, notify func(models.OltEvent)) *Receiver {
+	return &Receiver{
+		inv:     inv,
+		store:   store,
+		notify:  notify,
+		traps:   builtinTraps(),
+		unknown: map[string]time.Time{},
+	}
+}
+
+// LoadTrapMap adds the trap map file at path to the built-in one. It must
+// be called before ListenTraps.
+func (r *Receiver) LoadTrapMap(path string) error {
+	m, err := LoadTrapMap(path)
+	if err != nil {
+		return err
+	}
+	r.traps.merge(m)
+	return nil
+}
+
+// olt returns the enabled OLT whose host is ip. Inventory hosts may carry
+// an SSH port, which is ignored.
+func (r *Receiver) olt(ip string) (models.Olt, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.olts == nil || time.Since(r.loadedAt) > inventoryTTL {
+		rows, err := r.inv.GetEnabled()
+		if err != nil {
+			log.Printf("events: load inventory: %v", err)
+		} else {
+			r.olts = make(map[string]models.Olt, len(rows))
+			for _, o := range rows {
+				host := o.Host
+				if h, _, err := net.SplitHostPort(host); err == nil {
+					host = h
+				}
+				r.olts[host] = o
+			}
+			r.loadedAt = time.Now()
+		}
+	}
+
+	o, ok := r.olts[ip]
+	if !ok && r.logUnknown(ip) {
+		log.Printf("events: dropping messages from %s, not an enabled OLT", ip)
+	}
+	return o, ok
+}
+
+// logUnknown reports whether a message from the unknown source ip should be
+// logged, so a chatty stranger does not flood the log. r.mu must be held.
+func (r *Receiver) logUnknown(ip string) bool {
+	now := time.Now()
+	if last, ok := r.unknown[ip]; ok && now.Sub(last) < unknownTTL {
+		return false
+	}
+	if len(r.unknown) >= maxUnknown {
+		for k, last := range r.unknown {
+			if now.Sub(last) >= unknownTTL {
+				delete(r.unknown, k)
+			}
+		}
+		if len(r.unknown) >= maxUnknown {
+			clear(r.unknown)
+		}
+	}
+	r.unknown[ip] = now
+	return true
+}
+
+// handle classifies text received from ip at t and records it when it is a
+// known alarm. severity is the sender's own, if the protocol carries one.
+func (r *Receiver) handle(source, ip, text, severity string, t time.Time) {
+	o, ok := r.olt(ip)
+	if !ok {
+		return
+	}
+	a, ok := classify(text)
+	if !ok {
+		return
+	}
+	if a.severity != "" {
+		severity = a.severity
+	}
+
+	ev := models.OltEvent{
+		Host:       o.Host,
+		Device:     o.Name,
+		Site:       o.Site,
+		Source:     source,
+		Kind:       a.kind,
+		Severity:   severity,
+		Object:     a.object,
+		Cleared:    a.cleared,
+		Message:    text,
+		OccurredAt: t,
+	}
+	if err := r.store.Create(&ev); err != nil {
+		log.Printf("events: store %s event from %s: %v", ev.Kind, o.Host, err)
+		return
+	}
+	if r.notify != nil {
+		r.notify(ev)
+	}
+}
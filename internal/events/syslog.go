@@ -0,0 +1,138 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var syslogSeverities = []string{"emergency", "alert", "critical", "error", "warning", "notice", "info", "debug"}
+
+// syslogMessage is the part of an RFC 3164 or RFC 5424 message we keep.
+type syslogMessage struct {
+	Severity string
+	Time     time.Time
+	Host     string
+	Text     string
+}
+
+// parseSyslog reads one message in either format. Missing or unparsable
+// timestamps are left zero; a message without a PRI is kept whole.
+func parseSyslog(b []byte) syslogMessage {
+	s := strings.TrimRight(string(b), "\r\n\x00")
+	var m syslogMessage
+
+	if strings.HasPrefix(s, "<") {
+		if end := strings.IndexByte(s, '>'); end > 1 && end <= 4 {
+			if pri, err := strconv.Atoi(s[1:end]); err == nil {
+				m.Severity = syslogSeverities[pri&7]
+				s = s[end+1:]
+			}
+		}
+	}
+
+	// RFC 5424: VERSION SP TIMESTAMP SP HOSTNAME SP APP-NAME SP PROCID SP
+	// MSGID SP STRUCTURED-DATA [SP MSG]
+	if strings.HasPrefix(s, "1 ") {
+		f := strings.SplitN(s[2:], " ", 6)
+		if len(f) == 6 {
+			if t, err := time.Parse(time.RFC3339Nano, f[0]); err == nil {
+				m.Time = t
+			}
+			m.Host = nilValue(f[1])
+			// MSG may start with a UTF-8 BOM
+			m.Text = strings.TrimPrefix(skipStructuredData(f[5]), "\ufeff")
+			return m
+		}
+	}
+
+	// RFC 3164: "Mmm dd hh:mm:ss HOSTNAME TAG: MSG"
+	if len(s) >= 16 {
+		if t, err := time.ParseInLocation(time.Stamp, s[:15], time.Local); err == nil {
+			now := time.Now()
+			m.Time = t.AddDate(now.Year(), 0, 0)
+			if m.Time.After(now.Add(24 * time.Hour)) {
+				// December messages received in January
+				m.Time = m.Time.AddDate(-1, 0, 0)
+			}
+			rest := strings.TrimLeft(s[15:], " ")
+			if host, text, ok := strings.Cut(rest, " "); ok {
+				m.Host, s = host, text
+			}
+		}
+	}
+	m.Text = strings.TrimSpace(s)
+	return m
+}
+
+func nilValue(s string) string {
+	if s == "-" {
+		return ""
+	}
+	return s
+}
+
+// skipStructuredData drops the STRUCTURED-DATA field ("-" or one or more
+// [..] elements) from the start of s.
+func skipStructuredData(s string) string {
+	if strings.HasPrefix(s, "-") {
+		return strings.TrimPrefix(s[1:], " ")
+	}
+	for strings.HasPrefix(s, "[") {
+		esc := false
+		i := 1
+		for ; i < len(s); i++ {
+			if esc {
+				esc = false
+				continue
+			}
+			if s[i] == '\\' {
+				esc = true
+			} else if s[i] == ']' {
+				break
+			}
+		}
+		if i >= len(s) {
+			return ""
+		}
+		s = s[i+1:]
+	}
+	return strings.TrimPrefix(s, " ")
+}
+
+// ListenSyslog receives syslog over UDP on addr until ctx ends. It returns
+// once the socket is bound.
+func (r *Receiver) ListenSyslog(ctx context.Context, addr string) error {
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		pc.Close()
+	}()
+
+	go func() {
+		buf := make([]byte, 8192)
+		for {
+			n, from, err := pc.ReadFrom(buf)
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					log.Printf("events: syslog: %v", err)
+				}
+				return
+			}
+			ip := from.(*net.UDPAddr).IP.String()
+			m := parseSyslog(buf[:n])
+			if m.Time.IsZero() {
+				m.Time = time.Now()
+			}
+			r.handle("syslog", ip, m.Text, m.Severity, m.Time)
+		}
+	}()
+	return nil
+}
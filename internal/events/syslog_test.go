@@ -0,0 +1,86 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSyslog(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want syslogMessage
+	}{
+		{
+			name: "RFC 5424",
+			in:   "<131>1 2024-05-14T09:12:03.120+03:00 OLT-01 alarm - - - ONT 1/1/1/1/5 loss of signal major\n",
+			want: syslogMessage{
+				Severity: "error",
+				Time:     time.Date(2024, 5, 14, 9, 12, 3, 120e6, time.FixedZone("", 3*3600)),
+				Host:     "OLT-01",
+				Text:     "ONT 1/1/1/1/5 loss of signal major",
+			},
+		},
+		{
+			name: "RFC 5424 structured data and BOM",
+			in:   `<12>1 2024-05-14T06:12:03Z - isam - ID47 [exampleSDID@32473 iut="3" note="a\]b"][x@1 y="z"] ` + "\ufeffboard down lt:1/1/4",
+			want: syslogMessage{
+				Severity: "warning",
+				Time:     time.Date(2024, 5, 14, 6, 12, 3, 0, time.UTC),
+				Text:     "board down lt:1/1/4",
+			},
+		},
+		{
+			name: "no PRI",
+			in:   "dying gasp on ont 1/1/1/1/1\x00",
+			want: syslogMessage{Text: "dying gasp on ont 1/1/1/1/1"},
+		},
+		{
+			name: "bad PRI",
+			in:   "<abc>text",
+			want: syslogMessage{Text: "<abc>text"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSyslog([]byte(tt.in))
+			if got.Severity != tt.want.Severity || got.Host != tt.want.Host || got.Text != tt.want.Text || !got.Time.Equal(tt.want.Time) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSyslogRFC3164(t *testing.T) {
+	got := parseSyslog([]byte("<28>Jan  2 03:04:05 10.1.1.1 ISAM: protection switchover pon:1/1/8/9\r\n"))
+	if got.Severity != "warning" || got.Host != "10.1.1.1" || got.Text != "ISAM: protection switchover pon:1/1/8/9" {
+		t.Errorf("got %+v", got)
+	}
+	if got.Time.Month() != time.January || got.Time.Day() != 2 || got.Time.Hour() != 3 {
+		t.Errorf("time = %v", got.Time)
+	}
+	if got.Time.After(time.Now().Add(24 * time.Hour)) {
+		t.Errorf("time %v is in the future", got.Time)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		text string
+		want alarm
+		ok   bool
+	}{
+		{"ONT 1/1/1/1/5 loss of signal major", alarm{kind: KindLOS, object: "1/1/1/1/5", severity: "major"}, true},
+		{"alarm cleared: LOS on ont:1/1/2/3/17", alarm{kind: KindLOS, object: "1/1/2/3/17", cleared: true}, true},
+		{"ont 1/1/1/1/1 dying-gasp critical", alarm{kind: KindDyingGasp, object: "1/1/1/1/1", severity: "critical"}, true},
+		{"lt:1/1/4 board missing", alarm{kind: KindBoardDown, object: "1/1/4"}, true},
+		{"port protection switchover pon:1/1/8/9 minor", alarm{kind: KindProtectionSwitch, object: "1/1/8/9", severity: "minor"}, true},
+		{"user isadmin logged in", alarm{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := classify(tt.text)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("classify(%q) = %+v, %v, want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
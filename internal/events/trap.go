@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+const oidSnmpTrapOID = ".1.3.6.1.6.3.1.1.4.1.0"
+
+// ListenTraps receives SNMPv2c traps and informs on addr until ctx ends.
+// Traps whose community is not community are dropped; an empty community
+// accepts any. It returns once the socket is bound.
+func (r *Receiver) ListenTraps(ctx context.Context, addr, community string) error {
+	tl := gosnmp.NewTrapListener()
+	tl.Params = &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: community}
+	tl.OnNewTrap = func(p *gosnmp.SnmpPacket, from *net.UDPAddr) {
+		if p.Version != gosnmp.Version2c || (community != "" && p.Community != community) {
+			return
+		}
+		r.handle("trap", from.IP.String(), trapText(p, r.traps), "", time.Now())
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- tl.Listen(addr) }()
+	select {
+	case <-tl.Listening():
+	case err := <-errc:
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		tl.Close()
+	}()
+	return nil
+}
+
+// trapText renders the trap as one line, so traps are classified with the
+// same wording as syslog: the trap OID, every string varbind, and the
+// wording traps names for the trap, OID-valued varbinds and integer
+// varbinds.
+func trapText(p *gosnmp.SnmpPacket, traps *TrapMap) string {
+	var parts []string
+	for _, v := range p.Variables {
+		switch v.Type {
+		case gosnmp.ObjectIdentifier:
+			oid, _ := v.Value.(string)
+			if v.Name == oidSnmpTrapOID {
+				parts = append(parts, "trap="+oid)
+			}
+			if text, ok := traps.oid(oid); ok {
+				parts = append(parts, text)
+			}
+		case gosnmp.Integer:
+			if text, ok := traps.integer(v.Name, int(gosnmp.ToBigInt(v.Value).Int64())); ok {
+				parts = append(parts, text)
+			}
+		case gosnmp.OctetString:
+			if b, ok := v.Value.([]byte); ok && len(b) > 0 {
+				parts = append(parts, strings.TrimSpace(string(b)))
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
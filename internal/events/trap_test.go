@@ -0,0 +1,119 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+func testTraps(t *testing.T) *TrapMap {
+	t.Helper()
+	m := builtinTraps()
+	file, err := LoadTrapMap(filepath.Join("testdata", "traps.map"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.merge(file)
+	return m
+}
+
+func TestTrapText(t *testing.T) {
+	traps := testTraps(t)
+	trap := func(vars ...gosnmp.SnmpPDU) *gosnmp.SnmpPacket {
+		return &gosnmp.SnmpPacket{Variables: append([]gosnmp.SnmpPDU{
+			{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(1234)},
+			{Name: oidSnmpTrapOID, Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.637.1.2.0.1"},
+		}, vars...)}
+	}
+
+	tests := []struct {
+		name string
+		p    *gosnmp.SnmpPacket
+		want string
+		kind string
+	}{
+		{
+			name: "integer alarm type",
+			p: trap(
+				gosnmp.SnmpPDU{Name: ".1.3.6.1.4.1.637.1.2.1.1.2.4481", Type: gosnmp.Integer, Value: 12},
+				gosnmp.SnmpPDU{Name: ".1.3.6.1.4.1.637.1.2.1.1.5.4481", Type: gosnmp.OctetString, Value: []byte("ont:1/1/1/1/5 ")},
+			),
+			want: "trap=.1.3.6.1.4.1.637.1.2.0.1 alarm loss of signal ont:1/1/1/1/5",
+			kind: KindLOS,
+		},
+		{
+			name: "OID alarm type",
+			p:    trap(gosnmp.SnmpPDU{Name: ".1.3.6.1.4.1.637.1.2.1.1.3.4481", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.637.1.2.3.7"}),
+			want: "trap=.1.3.6.1.4.1.637.1.2.0.1 alarm board failure",
+			kind: KindBoardDown,
+		},
+		{
+			name: "unmapped value",
+			p:    trap(gosnmp.SnmpPDU{Name: ".1.3.6.1.4.1.637.1.2.1.1.2.4481", Type: gosnmp.Integer, Value: 99}),
+			want: "trap=.1.3.6.1.4.1.637.1.2.0.1 alarm",
+		},
+		{
+			name: "standard trap",
+			p: &gosnmp.SnmpPacket{Variables: []gosnmp.SnmpPDU{
+				{Name: oidSnmpTrapOID, Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.1"},
+			}},
+			want: "trap=.1.3.6.1.6.3.1.1.5.1 nt board reset (cold start)",
+			kind: KindBoardDown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := trapText(tt.p, traps)
+			if got != tt.want {
+				t.Fatalf("trapText = %q, want %q", got, tt.want)
+			}
+			a, ok := classify(got)
+			if ok != (tt.kind != "") || a.kind != tt.kind {
+				t.Errorf("classify = %q, %v, want %q", a.kind, ok, tt.kind)
+			}
+		})
+	}
+}
+
+func TestLoadTrapMapErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, line := range []string{"1.3.6.1.4.1.637.1", "1.3.6.1.4.1.637.1=x loss of signal"} {
+		path := filepath.Join(dir, "bad.map")
+		writeFile(t, path, line+"\n")
+		if _, err := LoadTrapMap(path); err == nil {
+			t.Errorf("%q: no error", line)
+		}
+	}
+}
+
+func TestReceiverForgetsUnknownSources(t *testing.T) {
+	r := NewReceiver(nil, nil, nil)
+	if !r.logUnknown("192.0.2.1") {
+		t.Fatal("first message not logged")
+	}
+	if r.logUnknown("192.0.2.1") {
+		t.Fatal("repeat within the TTL logged")
+	}
+	r.unknown["192.0.2.1"] = time.Now().Add(-unknownTTL)
+	if !r.logUnknown("192.0.2.1") {
+		t.Fatal("repeat after the TTL not logged")
+	}
+
+	for i := 0; i < 3*maxUnknown; i++ {
+		r.logUnknown(fmt.Sprintf("198.51.%d.%d", i/256, i%256))
+	}
+	if len(r.unknown) > maxUnknown {
+		t.Fatalf("%d sources remembered, want at most %d", len(r.unknown), maxUnknown)
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,118 @@
+package events
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// TrapMap turns the numeric parts of a trap into the alarm wording classify
+// knows from the alarm log: the trap OID and OID-valued varbinds (such as an
+// alarm type pointing at its definition) by OID, and integer varbinds that
+// carry an alarm type by column OID and value.
+type TrapMap struct {
+	OIDs map[string]string
+	Ints map[string]map[int]string
+}
+
+// builtinTraps covers the standard notifications. The ISAM alarm
+// notifications and alarm types differ by MIB release and are loaded with
+// LoadTrapMap.
+func builtinTraps() *TrapMap {
+	return &TrapMap{
+		OIDs: map[string]string{
+			"1.3.6.1.6.3.1.1.5.1": "nt board reset (cold start)",
+			"1.3.6.1.6.3.1.1.5.2": "nt board reset (warm start)",
+		},
+		Ints: map[string]map[int]string{},
+	}
+}
+
+// LoadTrapMap reads a trap map file. Each line is "<oid> <wording>" for a
+// trap or OID value, or "<column-oid>=<value> <wording>" for an integer
+// varbind; blank lines and lines starting with "#" are skipped. With
+// illustrative OIDs:
+//
+//	# alarm notification, and the alarm type column with its LOS value
+//	1.3.6.1.4.1.637.1.2.0.1 alarm
+//	1.3.6.1.4.1.637.1.2.1.1.2=12 loss of signal
+func LoadTrapMap(path string) (*TrapMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m := &TrapMap{OIDs: map[string]string{}, Ints: map[string]map[int]string{}}
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, text, ok := strings.Cut(line, " ")
+		text = strings.TrimSpace(text)
+		if !ok || text == "" {
+			return nil, fmt.Errorf("events: %s:%d: want \"<oid>[=<value>] <wording>\"", path, n)
+		}
+		oid, value, isInt := strings.Cut(key, "=")
+		oid = strings.TrimPrefix(oid, ".")
+		if !isInt {
+			m.OIDs[oid] = text
+			continue
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("events: %s:%d: value %q is not an integer", path, n, value)
+		}
+		if m.Ints[oid] == nil {
+			m.Ints[oid] = map[int]string{}
+		}
+		m.Ints[oid][v] = text
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// merge adds the entries of o, replacing ours.
+func (m *TrapMap) merge(o *TrapMap) {
+	for oid, text := range o.OIDs {
+		m.OIDs[oid] = text
+	}
+	for oid, values := range o.Ints {
+		if m.Ints[oid] == nil {
+			m.Ints[oid] = map[int]string{}
+		}
+		for v, text := range values {
+			m.Ints[oid][v] = text
+		}
+	}
+}
+
+// oid returns the wording of a trap or OID value.
+func (m *TrapMap) oid(oid string) (string, bool) {
+	text, ok := m.OIDs[strings.TrimPrefix(oid, ".")]
+	return text, ok
+}
+
+// integer returns the wording of value in the varbind name. name is a
+// column OID followed by the row index, so the longest mapped prefix wins.
+func (m *TrapMap) integer(name string, value int) (string, bool) {
+	col := strings.TrimPrefix(name, ".")
+	for col != "" {
+		if values, ok := m.Ints[col]; ok {
+			text, ok := values[value]
+			return text, ok
+		}
+		i := strings.LastIndexByte(col, '.')
+		if i < 0 {
+			break
+		}
+		col = col[:i]
+	}
+	return "", false
+}
@@ -1,6 +1,7 @@
 package excesscommands
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -11,10 +12,13 @@ import (
 	"github.com/Flafl/DevOpsCore/internal/shell"
 )
 
+// Backups saves the flat configuration of every Nokia OLT under backups/.
+// Like the scheduled backup it yields the broker to scans and user requests.
 func Backups(username, password string) {
 	cmd := "info configure flat"
+	ctx := shell.WithPriority(context.Background(), shell.PriorityLow)
 
-	for olt := range shell.SendCommandNokiaOLTs(username, password, cmd) {
+	for olt := range shell.SendCommandNokiaOLTsContext(ctx, username, password, cmd) {
 		if olt.Err != nil {
 			log.Printf("ERROR %s: %v", olt.Host, olt.Err)
 			continue
@@ -17,15 +17,19 @@ func CpuUpTime(username, password string) {
 	}
 
 	type HostHealth struct {
-		Host   string           `json:host`
-		Health extractor.Health `json:health`
+		Host   string           `json:"host"`
+		Health extractor.Health `json:"health"`
 	}
 	var results []HostHealth
-	for output := range shell.SendCommandNokiaOLTs(username, password, cmds...) {
+	for output := range shell.SendCommandNokiaOLTsContext(onDemand(), username, password, cmds...) {
 		if output.Err != nil {
 			fmt.Printf("ERROR %s: %v\n", output.Host, output.Err)
 		}
-		h := extractor.ExtractHealth(output.Data)
+		h := extractor.ExtractHealthFrom(
+			shell.Output(output.Commands, 0),
+			shell.Output(output.Commands, 1),
+			shell.Output(output.Commands, 2),
+		)
 		results = append(results, HostHealth{Host: output.Host, Health: h})
 	}
 	utils.SaveJSON("json", "olt-health", results)
@@ -77,7 +77,7 @@ func ExtractOntIDAndDesc(username, password string) []CmdResult {
 			parallelSessions <- struct{}{}
 			defer func() { <-parallelSessions }()
 
-			output, err := shell.NkSendCommandOLT(host, username, password, "show equipment ont status pon")
+			output, err := shell.NkSendCommandOLTContext(onDemand(), host, username, password, "show equipment ont status pon")
 			if err != nil {
 				log.Printf("ERROR: %s: %v", host, err)
 				return
@@ -23,7 +23,7 @@ func PortProtection(username, password string) []PortResult {
 	cmd := "show port-protection"
 	var output []PortResult
 
-	for olt := range shell.SendCommandNokiaOLTs(username, password, cmd) {
+	for olt := range shell.SendCommandNokiaOLTsContext(onDemand(), username, password, cmd) {
 		if olt.Err != nil {
 			output = append(output, PortResult{Host: olt.Host, Err: olt.Err})
 			continue
@@ -16,10 +16,12 @@ type PowersResult struct {
 	Err    error
 }
 
+// PowersLessThan24 lists the ONTs whose OLT Rx level (olt-rx-sig-level, the
+// upstream signal) is below -24 dBm, the same threshold as /api/power/weak.
 func PowersLessThan24(user, password string, allPower bool) ([]PowersResult, error) {
 	cmd := "show equipment ont optics"
 	out := make([]PowersResult, 0)
-	for r := range shell.SendCommandNokiaOLTs(user, password, cmd) {
+	for r := range shell.SendCommandNokiaOLTsContext(onDemand(), user, password, cmd) {
 		if r.Err != nil {
 			out = append(out, PowersResult{Device: r.Device, Site: r.Site, Host: r.Host, Err: r.Err})
 			continue
@@ -0,0 +1,13 @@
+package excesscommands
+
+import (
+	"context"
+
+	"github.com/Flafl/DevOpsCore/internal/shell"
+)
+
+// onDemand tags the sessions of these user-run commands so the broker
+// serves them before the scheduled scans.
+func onDemand() context.Context {
+	return shell.WithPriority(context.Background(), shell.PriorityHigh)
+}
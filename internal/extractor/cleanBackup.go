@@ -6,9 +6,23 @@ import (
 )
 
 var (
-	alarmLineRe  = regexp.MustCompile(`(?m)^\d{2}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} .+ alarm .+$`)
-	spinnerRe    = regexp.MustCompile(`[-\\|/]{2,}[-\\|/ ]*`)
+	alarmLineRe = regexp.MustCompile(`(?m)^\d{2}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} .+ alarm .+$`)
+	// The ISAM progress spinner is drawn at the start of a line before the
+	// output begins. Only runs there that contain a "\" or "|" are taken, so
+	// config lines holding "--" or "/" are never touched. Sessions opened
+	// with "environment mode batch" have no spinner at all.
+	spinnerRe    = regexp.MustCompile(`(?m)^[-/ ]*[\\|][-\\|/ ]*`)
 	blankLinesRe = regexp.MustCompile(`\n{3,}`)
+
+	// "---- More ( Press 'Q' to break ) ----" followed by the cursor moves
+	// and blanks the MA5800 uses to erase it before the next line.
+	hwMoreRe = regexp.MustCompile(`[ \t]*---- More[^\n\x1b]*----(?:\x1b\[\d+D(?:[ \t]*\x1b\[\d+D)?)?`)
+	ansiRe   = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+	// An MA5800 alarm is a "! <severity> <date>" line plus indented
+	// "NAME : value" or "name = value" detail lines and an optional "--- END".
+	// Alarms can land inside an indented config block, so a line that is not
+	// in the detail format ends the alarm.
+	hwAlarmRe = regexp.MustCompile(`(?m)^[ \t]*! ?(?:CRITICAL|MAJOR|MINOR|WARNING|EVENT|RECOVERY|CLEARED)[ \t]+\d{4}-\d{2}-\d{2}[^\n]*\n(?:[ \t]+[A-Za-z][A-Za-z ]*?[ \t]*[:=][^\n]*\n)*(?:[ \t]*-+[ \t]*END[ \t]*\n)?`)
 )
 
 func CleanBackupOutput(raw string) string {
@@ -18,3 +32,14 @@ func CleanBackupOutput(raw string) string {
 	out = strings.TrimSpace(out)
 	return out
 }
+
+// CleanHuaweiBackupOutput strips pager prompts, terminal escapes and
+// interleaved alarms from "display current-configuration".
+func CleanHuaweiBackupOutput(raw string) string {
+	out := strings.ReplaceAll(raw, "\r\n", "\n")
+	out = hwMoreRe.ReplaceAllString(out, "")
+	out = ansiRe.ReplaceAllString(out, "")
+	out = hwAlarmRe.ReplaceAllString(out, "")
+	out = blankLinesRe.ReplaceAllString(out, "\n\n")
+	return strings.TrimSpace(out)
+}
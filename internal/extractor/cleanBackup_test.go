@@ -0,0 +1,24 @@
+package extractor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanHuaweiBackupOutput(t *testing.T) {
+	raw := readFixture(t, "huawei/display-current-configuration.txt")
+	want := strings.TrimSpace(readFixture(t, "huawei/display-current-configuration.clean.txt"))
+	if got := CleanHuaweiBackupOutput(raw); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCleanHuaweiBackupOutputPager(t *testing.T) {
+	raw := "sysname OLT-HW-01\r\n" +
+		"  ---- More ( Press 'Q' to break ) ----\x1b[37D                                     \x1b[37D" +
+		" interface gpon 0/1\r\n"
+	want := "sysname OLT-HW-01\n interface gpon 0/1"
+	if got := CleanHuaweiBackupOutput(raw); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -29,34 +29,41 @@ func ExtractAllDesc(output string) []OntDesc {
 
 	results := make([]OntDesc, 0, len(matches))
 	for _, m := range matches {
+		desc1, desc2 := cleanDesc(m[3], m[4])
+		results = append(results, OntDesc{
+			OntIdx: m[1],
+			Desc1:  desc1,
+			Desc2:  desc2,
+		})
+	}
+	return results
+}
 
+var reDescGap = regexp.MustCompile(`\s{2,}`)
 
-		desc1 := strings.Trim(m[3], `"`)
-		desc1 = strings.NewReplacer("\t", "", "\n", "").Replace(desc1)
-		desc1 = strings.TrimSpace(desc1)
+// cleanDesc tidies the desc1 and desc2 columns of the status table: quotes,
+// tabs, newlines, replacement characters, "*" and the trailing "undefined"
+// go, and an empty desc1 is split off a desc2 that holds both.
+func cleanDesc(desc1, desc2 string) (string, string) {
+	desc1 = strings.Trim(desc1, `"`)
+	desc1 = strings.NewReplacer("\t", "", "\n", "").Replace(desc1)
+	desc1 = strings.TrimSpace(desc1)
 
-		desc2 := strings.TrimSpace(strings.Trim(m[4], `"`))
-		desc2 = strings.TrimSuffix(desc2, "undefined")
-		desc2 = strings.TrimSpace(desc2)
-		desc2 = strings.NewReplacer("\t", "", "\n", "", "\ufffd", "", "*", "").Replace(desc2)
-		desc2 = strings.TrimRight(desc2, `" `)
-		desc2 = strings.TrimSpace(desc2)
+	desc2 = strings.TrimSpace(strings.Trim(desc2, `"`))
+	desc2 = strings.TrimSuffix(desc2, "undefined")
+	desc2 = strings.TrimSpace(desc2)
+	desc2 = strings.NewReplacer("\t", "", "\n", "", "\ufffd", "", "*", "").Replace(desc2)
+	desc2 = strings.TrimRight(desc2, `" `)
+	desc2 = strings.TrimSpace(desc2)
 
-		if desc1 == "" {
-			parts := regexp.MustCompile(`\s{2,}`).Split(desc2, 2)
-			if len(parts) == 2 {
-				desc1 = strings.Trim(parts[0], `"`)
-				desc2 = strings.Trim(parts[1], `"`)
-			}
+	if desc1 == "" {
+		parts := reDescGap.Split(desc2, 2)
+		if len(parts) == 2 {
+			desc1 = strings.Trim(parts[0], `"`)
+			desc2 = strings.Trim(parts[1], `"`)
 		}
-
-		results = append(results, OntDesc{
-			OntIdx: m[1],
-			Desc1:  strings.ToValidUTF8(desc1, ""),
-			Desc2:  strings.ToValidUTF8(desc2, ""),
-		})
 	}
-	return results
+	return strings.ToValidUTF8(desc1, ""), strings.ToValidUTF8(desc2, "")
 }
 
 func ExtractDesc (line string) (rx float64, desc1, desc2 string, ok bool){
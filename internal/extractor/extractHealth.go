@@ -31,30 +31,50 @@ var (
 	reTemp   = regexp.MustCompile(`(?m)^((?:nt-[ab]|lt:\S+))\s+(\d+)\s+(\d+)\s+\d+\s+(\d+)\s+\d+\s+(\d+)`)
 )
 
+// ExtractHealth parses the CPU load, uptime and temperature commands from
+// one blob. Prefer ExtractHealthFrom when the outputs are available per
+// command.
 func ExtractHealth(output string) Health {
-	var h Health
+	return ExtractHealthFrom(output, output, output)
+}
+
+// ExtractHealthFrom builds Health from the outputs of the CPU load, uptime
+// and temperature commands, each parsed on its own.
+func ExtractHealthFrom(cpu, uptime, temp string) Health {
+	return Health{
+		Uptime:       ExtractUptime(uptime),
+		CpuLoads:     ExtractCpuLoads(cpu),
+		Temperatures: ExtractTemperatures(temp),
+	}
+}
 
-	// CPU loads
+func ExtractCpuLoads(output string) []CpuLoad {
+	var loads []CpuLoad
 	for _, m := range reCpu.FindAllStringSubmatch(output, -1) {
 		avg, _ := strconv.Atoi(m[2])
-		h.CpuLoads = append(h.CpuLoads, CpuLoad{
+		loads = append(loads, CpuLoad{
 			Slot:    m[1],
 			Average: avg,
 		})
 	}
+	return loads
+}
 
-	// Uptime
+func ExtractUptime(output string) string {
 	if m := reUptime.FindStringSubmatch(output); m != nil {
-		h.Uptime = strings.TrimSpace(m[1])
+		return strings.TrimSpace(m[1])
 	}
+	return ""
+}
 
-	// Temperatures
+func ExtractTemperatures(output string) []Temperature {
+	var temps []Temperature
 	for _, m := range reTemp.FindAllStringSubmatch(output, -1) {
 		sid, _ := strconv.Atoi(m[2])
 		act, _ := strconv.Atoi(m[3])
 		tcaH, _ := strconv.Atoi(m[4])
 		shutH, _ := strconv.Atoi(m[5])
-		h.Temperatures = append(h.Temperatures, Temperature{
+		temps = append(temps, Temperature{
 			Slot:     m[1],
 			SensorID: sid,
 			ActTemp:  act,
@@ -62,6 +82,5 @@ func ExtractHealth(output string) Health {
 			ShutHigh: shutH,
 		})
 	}
-
-	return h
-}
\ No newline at end of file
+	return temps
+}
@@ -0,0 +1,103 @@
+package extractor
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HuaweiBoard is one row of "display board <frame>".
+type HuaweiBoard struct {
+	Slot   int
+	Name   string
+	Status string
+}
+
+var (
+	reHwBoard = regexp.MustCompile(`(?m)^[ \t]*(\d+)[ \t]+(\S+)[ \t]+(\S+)`)
+	// "display ont optical-info 0/1/0 all" -> 0/1/0
+	reHwOpticsPort = regexp.MustCompile(`optical-info\s+(\d+/\d+/\d+)\b`)
+	// F/S/P is printed as "0/ 1/0" in "display ont info" tables.
+	reHwOntRow = regexp.MustCompile(`^(\d+)/\s*(\d+)/\s*(\d+)\s+(\d+)(?:\s+(.*))?$`)
+)
+
+// ExtractHuaweiBoards lists the equipped slots of "display board <frame>".
+// Empty slots are left out.
+func ExtractHuaweiBoards(output string) []HuaweiBoard {
+	var out []HuaweiBoard
+	for _, m := range reHwBoard.FindAllStringSubmatch(output, -1) {
+		slot, _ := strconv.Atoi(m[1])
+		out = append(out, HuaweiBoard{Slot: slot, Name: m[2], Status: m[3]})
+	}
+	return out
+}
+
+// ExtractHuaweiOntPower parses "display ont optical-info <f/s/p> all". cmd is
+// the command that produced output; its frame/slot/port prefixes each ONT ID
+// so indexes read "0/1/0/5", like the Nokia rack/shelf/slot/port/ont ones.
+func ExtractHuaweiOntPower(cmd, output string) []OntPower {
+	m := reHwOpticsPort.FindStringSubmatch(cmd)
+	if m == nil {
+		return nil
+	}
+	port := m[1]
+
+	var out []OntPower
+	sc := bufio.NewScanner(strings.NewReader(output))
+	sc.Buffer(make([]byte, 1024), 1024*1024)
+	for sc.Scan() {
+		// ONT ID, ONT Rx, ONT Tx, OLT Rx, temperature, voltage, current
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			continue
+		}
+		oltRx, ok := parseFloat(fields[3])
+		if !ok {
+			continue
+		}
+		p := OntPower{OntIdx: port + "/" + fields[0], OltRx: oltRx}
+		p.OntRx = optionalFloat(fields, 1)
+		p.OntTx = optionalFloat(fields, 2)
+		p.Temperature = optionalFloat(fields, 4)
+		p.Voltage = optionalFloat(fields, 5)
+		p.LaserBias = optionalFloat(fields, 6)
+		out = append(out, p)
+	}
+	return out
+}
+
+// ExtractHuaweiDesc parses the description table of "display ont info <frame>
+// all". Huawei keeps a single description per ONT, returned as Desc1.
+func ExtractHuaweiDesc(output string) []OntDesc {
+	output = strings.ToValidUTF8(output, "")
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+
+	var out []OntDesc
+	inDesc := false
+	sc := bufio.NewScanner(strings.NewReader(output))
+	sc.Buffer(make([]byte, 1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "F/S/P") {
+			// the first table is state, the one headed "Description" is ours
+			inDesc = strings.Contains(line, "Description")
+			continue
+		}
+		if !inDesc {
+			continue
+		}
+		m := reHwOntRow.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		out = append(out, OntDesc{
+			OntIdx: m[1] + "/" + m[2] + "/" + m[3] + "/" + m[4],
+			Desc1:  strings.TrimSpace(m[5]),
+		})
+	}
+	return out
+}
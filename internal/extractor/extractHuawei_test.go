@@ -0,0 +1,59 @@
+package extractor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractHuaweiBoards(t *testing.T) {
+	got := ExtractHuaweiBoards(readFixture(t, "huawei/display-board-0.txt"))
+	want := []HuaweiBoard{
+		{Slot: 1, Name: "H901GPHF", Status: "Normal"},
+		{Slot: 2, Name: "H901GPHF", Status: "Failed"},
+		{Slot: 9, Name: "H902MPLA", Status: "Active_normal"},
+		{Slot: 10, Name: "H902MPLA", Status: "Standby_normal"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestExtractHuaweiOntPower(t *testing.T) {
+	out := readFixture(t, "huawei/display-ont-optical-info.txt")
+
+	got := ExtractHuaweiOntPower("display ont optical-info 0/1/3 all", out)
+	want := []OntPower{
+		{OntIdx: "0/1/3/0", OltRx: -21.43, OntRx: ptr(-19.52), OntTx: ptr(2.31), Temperature: ptr(45), Voltage: ptr(3.28), LaserBias: ptr(12)},
+		{OntIdx: "0/1/3/1", OltRx: -26.87, OntRx: ptr(-24.1), OntTx: ptr(2.05), Temperature: ptr(51), Voltage: ptr(3.3), LaserBias: ptr(15)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d ONTs, want %d (offline skipped): %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.OntIdx != w.OntIdx || g.OltRx != w.OltRx ||
+			!floatEq(g.OntRx, w.OntRx) || !floatEq(g.OntTx, w.OntTx) ||
+			!floatEq(g.Temperature, w.Temperature) || !floatEq(g.Voltage, w.Voltage) ||
+			!floatEq(g.LaserBias, w.LaserBias) {
+			t.Errorf("ONT %d = %s olt=%v rx=%v tx=%v temp=%v v=%v bias=%v", i,
+				g.OntIdx, g.OltRx, deref(g.OntRx), deref(g.OntTx),
+				deref(g.Temperature), deref(g.Voltage), deref(g.LaserBias))
+		}
+	}
+
+	if got := ExtractHuaweiOntPower("display board 0", out); got != nil {
+		t.Errorf("output of another command parsed: %+v", got)
+	}
+}
+
+func TestExtractHuaweiDesc(t *testing.T) {
+	got := ExtractHuaweiDesc(readFixture(t, "huawei/display-ont-info-0-all.txt"))
+	want := []OntDesc{
+		{OntIdx: "0/1/0/0", Desc1: "CUST-2001 Oak Road 4"},
+		{OntIdx: "0/1/0/1"},
+		{OntIdx: "0/1/1/12", Desc1: "CUST-2012"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+}
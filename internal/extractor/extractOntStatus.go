@@ -0,0 +1,51 @@
+package extractor
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+)
+
+// OntStatus is one row of "show equipment ont status pon". OltRx and
+// DistanceKm are nil when the OLT does not know them.
+type OntStatus struct {
+	Pon         string
+	OntIdx      string
+	Sernum      string
+	AdminStatus string
+	OperStatus  string
+	OltRx       *float64
+	DistanceKm  *float64
+}
+
+// pon, ont, sernum, admin-status, oper-status, olt-rx-sig-level,
+// ont-olt-distance(km); the descriptions that follow are left to
+// ExtractAllDesc.
+var reOntStatus = regexp.MustCompile(`^\s*(\d+(?:/\d+)+)\s+(\d+(?:/\d+)+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(\S+)`)
+
+// ExtractOntStatus parses the status table of "show equipment ont status
+// pon".
+func ExtractOntStatus(output string) []OntStatus {
+	output = strings.ToValidUTF8(output, "")
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+
+	var out []OntStatus
+	sc := bufio.NewScanner(strings.NewReader(output))
+	sc.Buffer(make([]byte, 1024), 1024*1024)
+	for sc.Scan() {
+		m := reOntStatus.FindStringSubmatch(sc.Text())
+		if m == nil {
+			continue
+		}
+		out = append(out, OntStatus{
+			Pon:         m[1],
+			OntIdx:      m[2],
+			Sernum:      m[3],
+			AdminStatus: m[4],
+			OperStatus:  m[5],
+			OltRx:       optionalFloat(m, 6),
+			DistanceKm:  optionalFloat(m, 7),
+		})
+	}
+	return out
+}
@@ -0,0 +1,37 @@
+package extractor
+
+import "testing"
+
+func TestExtractOntStatus(t *testing.T) {
+	got := ExtractOntStatus(simulatorFixture(t, "show-equipment-ont-status-pon.txt"))
+	if len(got) != 6 {
+		t.Fatalf("got %d ONTs, want 6", len(got))
+	}
+
+	first := OntStatus{
+		Pon: "1/1/1/1", OntIdx: "1/1/1/1/1", Sernum: "HWTC:98083F70",
+		AdminStatus: "up", OperStatus: "up", OltRx: ptr(-24.437), DistanceKm: ptr(1.204),
+	}
+	if g := got[0]; g.Pon != first.Pon || g.OntIdx != first.OntIdx || g.Sernum != first.Sernum ||
+		g.AdminStatus != first.AdminStatus || g.OperStatus != first.OperStatus ||
+		!floatEq(g.OltRx, first.OltRx) || !floatEq(g.DistanceKm, first.DistanceKm) {
+		t.Errorf("first ONT = %+v rx=%v km=%v", g, deref(g.OltRx), deref(g.DistanceKm))
+	}
+	if g := got[5]; g.OntIdx != "1/1/1/1/6" || g.OperStatus != "down" {
+		t.Errorf("last ONT = %s %s, want 1/1/1/1/6 down", g.OntIdx, g.OperStatus)
+	}
+}
+
+func TestExtractOntStatusUnknownReadings(t *testing.T) {
+	out := "1/1/1/2        1/1/1/2/7      ALCL:B3C4D5E6  up           down        invalid          unknown              undefined undefined undefined\r\n"
+	got := ExtractOntStatus(out)
+	if len(got) != 1 {
+		t.Fatalf("got %d ONTs, want 1", len(got))
+	}
+	if got[0].OltRx != nil || got[0].DistanceKm != nil {
+		t.Errorf("readings = %v, %v, want nil", deref(got[0].OltRx), deref(got[0].DistanceKm))
+	}
+	if got[0].Sernum != "ALCL:B3C4D5E6" {
+		t.Errorf("sernum = %q", got[0].Sernum)
+	}
+}
@@ -11,7 +11,7 @@ type PortProtection struct {
 	PairedState string  `json:"paired_state"`
 	SwoReason	string  `json:"swo_reason"`
 	NumSwo		int     `json:"num_swo"`
-	alert     	string  `json:"alert"`
+	alert     	string
 }
 
 var rePort = regexp.MustCompile(
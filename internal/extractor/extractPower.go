@@ -6,9 +6,28 @@ import (
 	"strings"
 )
 
+// OntPower is one ONT's optics. OltRx is the upstream level received by the
+// OLT; the other readings are nil when the device does not report them, e.g.
+// "unknown" for an ONT that has not answered.
 type OntPower struct {
 	OntIdx string
 	OltRx  float64
+
+	OntRx       *float64 // downstream level received by the ONT, dBm
+	OntTx       *float64 // ONT laser output, dBm
+	LaserBias   *float64 // ONT laser bias current, as reported
+	Temperature *float64 // ONT temperature, Celsius
+	Voltage     *float64 // ONT supply voltage, V
+}
+
+// opticsColumns maps the "show equipment ont optics" headers to the
+// OntPower readings they fill.
+var opticsColumns = map[string]func(p *OntPower, v float64){
+	"rx-signal-level": func(p *OntPower, v float64) { p.OntRx = &v },
+	"tx-signal-level": func(p *OntPower, v float64) { p.OntTx = &v },
+	"ont-voltage":     func(p *OntPower, v float64) { p.Voltage = &v },
+	"ont-temperature": func(p *OntPower, v float64) { p.Temperature = &v },
+	"laser-bias-curr": func(p *OntPower, v float64) { p.LaserBias = &v },
 }
 
 func ExtractOntIdxBelowOltRx(output string, threshold float64) []string {
@@ -34,10 +53,20 @@ func ExtractAllOntPower(output string) []OntPower {
 	sc := bufio.NewScanner(strings.NewReader(table))
 	sc.Buffer(make([]byte, 1024), 1024*1024)
 
+	// columns are found by header, falling back to ont-idx and the Rx
+	// level in the first two when there is none
+	header := []string{"ont-idx", "olt-rx-sig-level"}
 	inRows := false
 	for sc.Scan() {
 		line := strings.TrimSpace(sc.Text())
 
+		if strings.HasPrefix(line, "ont-idx") {
+			header = strings.Split(line, "|")
+			for i := range header {
+				header[i] = strings.TrimSpace(header[i])
+			}
+			continue
+		}
 		if strings.HasPrefix(line, "--------------+") {
 			inRows = true
 			continue
@@ -56,18 +85,37 @@ func ExtractAllOntPower(output string) []OntPower {
 		if len(fields) < 2 {
 			continue
 		}
-		ontIdx := fields[0]
-		oltRxStr := fields[1]
-
-		oltRx, ok := parseFloat(oltRxStr)
+		p, ok := opticsRow(header, fields)
 		if !ok {
 			continue
 		}
-		out = append(out, OntPower{OntIdx: ontIdx, OltRx: oltRx})
+		out = append(out, p)
 	}
 	return out
 }
 
+// opticsRow reads one row of the optics table. Rows without a numeric OLT
+// Rx level, e.g. offline ONTs, are skipped.
+func opticsRow(header, fields []string) (OntPower, bool) {
+	p := OntPower{OntIdx: fields[0]}
+	hasOltRx := false
+	for i, name := range header {
+		if i == 0 || i >= len(fields) {
+			continue
+		}
+		v, ok := parseFloat(fields[i])
+		if !ok {
+			continue
+		}
+		if name == "olt-rx-sig-level" {
+			p.OltRx, hasOltRx = v, true
+		} else if set, known := opticsColumns[name]; known {
+			set(&p, v)
+		}
+	}
+	return p, hasOltRx
+}
+
 func ExtractOntPowerBelowOltRx(output string, threshold float64) []OntPower {
 	all := ExtractAllOntPower(output)
 	var out []OntPower
@@ -87,6 +135,19 @@ func parseFloat(s string) (float64, bool) {
 	return v, true
 }
 
+// optionalFloat parses fields[i], or returns nil when it is missing or not a
+// number.
+func optionalFloat(fields []string, i int) *float64 {
+	if i >= len(fields) {
+		return nil
+	}
+	v, ok := parseFloat(fields[i])
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
 func extractOpticsTableSection(out string) (string, bool) {
 
 	start := strings.Index(out, "optics table")
@@ -0,0 +1,187 @@
+package extractor
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ZteCard is one row of "show card":
+//
+//	Rack Shelf Slot CfgType RealType Port  HardVer SoftVer  Status
+//	1    1     2    GTGH    GTGH     16    V1.0.0  V2.1.0   INSERVICE
+type ZteCard struct {
+	Shelf  int
+	Slot   int
+	Type   string
+	Ports  int
+	Status string
+}
+
+// GPON reports whether the card is a GPON line card (GTGx on C300, GFGx on
+// the TITAN platform).
+func (c ZteCard) GPON() bool {
+	return strings.HasPrefix(c.Type, "GTG") || strings.HasPrefix(c.Type, "GFG")
+}
+
+func (c ZteCard) InService() bool {
+	return strings.EqualFold(c.Status, "INSERVICE")
+}
+
+var (
+	// gpon-onu_1/2/1:5 on C300, gpon_onu-1/2/1:5 on C600
+	reZteOnu = regexp.MustCompile(`gpon[-_]onu[-_](\d+/\d+/\d+):(\d+)`)
+	// " up      Rx :-22.548(dbm)      Tx:2.140(dbm)        24.688(dB)"
+	reZteUpRx = regexp.MustCompile(`(?i)\bup\s+rx\s*:\s*(-?\d+(?:\.\d+)?)(?:\S*\s+tx\s*:\s*(-?\d+(?:\.\d+)?))?`)
+	// " down    Tx :6.543(dbm)        Rx:-18.210(dbm)      24.753(dB)"
+	reZteDownRx    = regexp.MustCompile(`(?i)\bdown\s+tx\s*:\s*\S+\s+rx\s*:\s*(-?\d+(?:\.\d+)?)`)
+	reZteCpu       = regexp.MustCompile(`(?m)^[ \t]*(\d+)[ \t]+(\d+)[ \t]+(\d+)%[ \t]+(\d+)%[ \t]+(\d+)%`)
+	reZteUptime    = regexp.MustCompile(`(?im)up\s*time\s*(?:is|:)\s*(.+?)\s*$`)
+	reZteTemp      = regexp.MustCompile(`(?m)^[ \t]*(\d+)[ \t]+(\d+)[ \t]+(-?\d+)(?:[ \t]+(-?\d+))?(?:[ \t]+(-?\d+))?[ \t]*$`)
+	reZteMore      = regexp.MustCompile(`[ \t]*--More--[ \t]*(?:[\x08]+[ \t]*[\x08]*)?`)
+	reZteInterface = regexp.MustCompile(`^interface\s+(\S+)`)
+)
+
+// ExtractZteCards parses "show card". Rows without a real type (empty or
+// unplugged slots) still appear, with their configured type.
+func ExtractZteCards(output string) []ZteCard {
+	var out []ZteCard
+	sc := bufio.NewScanner(strings.NewReader(output))
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) < 6 {
+			continue
+		}
+		if _, err := strconv.Atoi(f[0]); err != nil {
+			continue
+		}
+		shelf, err1 := strconv.Atoi(f[1])
+		slot, err2 := strconv.Atoi(f[2])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		c := ZteCard{Shelf: shelf, Slot: slot, Type: f[3], Status: f[len(f)-1]}
+		// Port follows RealType, which is blank for unplugged cards
+		for _, s := range f[4:6] {
+			if n, err := strconv.Atoi(s); err == nil {
+				c.Ports = n
+				break
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// ExtractZteOntPower parses "show pon power attenuation <port>". Each ONU is
+// introduced by its interface name; its "up" line carries the OLT Rx and ONU
+// Tx power and its "down" line the ONU Rx. Indexes read shelf/slot/port/onu,
+// e.g. "1/2/1/5". Offline ONUs (N/A) are skipped.
+func ExtractZteOntPower(output string) []OntPower {
+	var out []OntPower
+	current := ""
+	sc := bufio.NewScanner(strings.NewReader(output))
+	sc.Buffer(make([]byte, 1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if m := reZteOnu.FindStringSubmatch(line); m != nil {
+			current = m[1] + "/" + m[2]
+		}
+		if current == "" {
+			continue
+		}
+		if m := reZteUpRx.FindStringSubmatch(line); m != nil {
+			if rx, ok := parseFloat(m[1]); ok {
+				out = append(out, OntPower{OntIdx: current, OltRx: rx, OntTx: optionalFloat(m, 2)})
+			}
+		}
+		if m := reZteDownRx.FindStringSubmatch(line); m != nil && len(out) > 0 && out[len(out)-1].OntIdx == current {
+			out[len(out)-1].OntRx = optionalFloat(m, 1)
+		}
+	}
+	return out
+}
+
+// ExtractZteDesc collects the "name" (Desc1) and "description" (Desc2) of
+// every ONU interface in "show running-config".
+func ExtractZteDesc(output string) []OntDesc {
+	output = strings.ToValidUTF8(output, "")
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+
+	var out []OntDesc
+	var cur *OntDesc
+	flush := func() {
+		if cur != nil && (cur.Desc1 != "" || cur.Desc2 != "") {
+			out = append(out, *cur)
+		}
+		cur = nil
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(output))
+	sc.Buffer(make([]byte, 1024), 16*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if m := reZteInterface.FindStringSubmatch(line); m != nil {
+			flush()
+			if o := reZteOnu.FindStringSubmatch(m[1]); o != nil {
+				cur = &OntDesc{OntIdx: o[1] + "/" + o[2]}
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		switch {
+		case line == "!" || line == "$":
+			flush()
+		case strings.HasPrefix(line, "name "):
+			cur.Desc1 = strings.Trim(strings.TrimSpace(line[len("name "):]), `"`)
+		case strings.HasPrefix(line, "description "):
+			cur.Desc2 = strings.Trim(strings.TrimSpace(line[len("description "):]), `"`)
+		}
+	}
+	flush()
+	return out
+}
+
+// ExtractZteHealthFrom builds Health from "show processor", "show
+// system-group" and "show temperature".
+func ExtractZteHealthFrom(cpu, uptime, temp string) Health {
+	var h Health
+
+	// Shelf Slot CPU(5s) CPU(1m) CPU(5m) ...; the 5 minute load is kept
+	for _, m := range reZteCpu.FindAllStringSubmatch(cpu, -1) {
+		avg, _ := strconv.Atoi(m[5])
+		h.CpuLoads = append(h.CpuLoads, CpuLoad{Slot: m[1] + "/" + m[2], Average: avg})
+	}
+
+	if m := reZteUptime.FindStringSubmatch(uptime); m != nil {
+		h.Uptime = m[1]
+	}
+
+	// Shelf Slot Temperature [High-threshold [Shutdown-threshold]]
+	for _, m := range reZteTemp.FindAllStringSubmatch(temp, -1) {
+		act, _ := strconv.Atoi(m[3])
+		high, _ := strconv.Atoi(m[4])
+		shut, _ := strconv.Atoi(m[5])
+		h.Temperatures = append(h.Temperatures, Temperature{
+			Slot:     m[1] + "/" + m[2],
+			ActTemp:  act,
+			TcaHigh:  high,
+			ShutHigh: shut,
+		})
+	}
+	return h
+}
+
+// CleanZteBackupOutput strips pager prompts and terminal escapes from "show
+// running-config".
+func CleanZteBackupOutput(raw string) string {
+	out := strings.ReplaceAll(raw, "\r\n", "\n")
+	out = reZteMore.ReplaceAllString(out, "")
+	out = ansiRe.ReplaceAllString(out, "")
+	out = strings.ReplaceAll(out, "\x08", "")
+	out = blankLinesRe.ReplaceAllString(out, "\n\n")
+	return strings.TrimSpace(out)
+}
@@ -0,0 +1,98 @@
+package extractor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractZteCards(t *testing.T) {
+	got := ExtractZteCards(readFixture(t, "zte/show-card.txt"))
+	want := []ZteCard{
+		{Shelf: 1, Slot: 2, Type: "GTGH", Ports: 16, Status: "INSERVICE"},
+		{Shelf: 1, Slot: 3, Type: "GTGH", Ports: 16, Status: "OFFLINE"},
+		{Shelf: 1, Slot: 10, Type: "SCXN", Ports: 4, Status: "INSERVICE"},
+		{Shelf: 1, Slot: 19, Type: "HUVQ", Ports: 4, Status: "INSERVICE"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+
+	var gpon []int
+	for _, c := range got {
+		if c.GPON() && c.InService() {
+			gpon = append(gpon, c.Slot)
+		}
+	}
+	if !reflect.DeepEqual(gpon, []int{2}) {
+		t.Errorf("in-service GPON slots = %v, want [2]", gpon)
+	}
+}
+
+func TestExtractZteOntPower(t *testing.T) {
+	got := ExtractZteOntPower(readFixture(t, "zte/show-pon-power-attenuation.txt"))
+	want := []OntPower{
+		{OntIdx: "1/2/1/1", OltRx: -22.548, OntTx: ptr(2.14), OntRx: ptr(-18.21)},
+		{OntIdx: "1/2/1/3", OltRx: -27.01, OntTx: ptr(1.98), OntRx: ptr(-21.4)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d ONUs, want %d (N/A skipped): %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.OntIdx != w.OntIdx || g.OltRx != w.OltRx || !floatEq(g.OntTx, w.OntTx) || !floatEq(g.OntRx, w.OntRx) {
+			t.Errorf("ONU %d = %s %v tx=%v rx=%v, want %s %v tx=%v rx=%v", i,
+				g.OntIdx, g.OltRx, deref(g.OntTx), deref(g.OntRx),
+				w.OntIdx, w.OltRx, deref(w.OntTx), deref(w.OntRx))
+		}
+	}
+}
+
+func TestExtractZteDesc(t *testing.T) {
+	got := ExtractZteDesc(readFixture(t, "zte/show-running-config.txt"))
+	want := []OntDesc{
+		{OntIdx: "1/2/1/1", Desc1: "CUST-1001", Desc2: "Main St 12"},
+		{OntIdx: "1/2/1/3", Desc1: "CUST-1003"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestExtractZteHealthFrom(t *testing.T) {
+	got := ExtractZteHealthFrom(
+		readFixture(t, "zte/show-processor.txt"),
+		readFixture(t, "zte/show-system-group.txt"),
+		readFixture(t, "zte/show-temperature.txt"),
+	)
+	want := Health{
+		Uptime:   "12 days 4 hours 33 minutes 10 seconds",
+		CpuLoads: []CpuLoad{{Slot: "1/2", Average: 9}, {Slot: "1/10", Average: 28}},
+		Temperatures: []Temperature{
+			{Slot: "1/2", ActTemp: 48, TcaHigh: 75, ShutHigh: 85},
+			{Slot: "1/10", ActTemp: 52, TcaHigh: 80},
+			{Slot: "1/19", ActTemp: 41},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestCleanZteBackupOutput(t *testing.T) {
+	raw := "\x1b[0m!\r\n" +
+		"interface gpon-onu_1/2/1:1\r\n" +
+		"  name CUST-1001\r\n" +
+		" --More-- \x08\x08\x08\x08\x08\x08\x08\x08\x08\x08          \x08\x08\x08\x08\x08\x08\x08\x08\x08\x08" +
+		"  description \"Main St 12\"\r\n" +
+		"\r\n\r\n\r\n\r\n" +
+		"!\r\nend\r\n"
+	want := "!\n" +
+		"interface gpon-onu_1/2/1:1\n" +
+		"  name CUST-1001\n" +
+		"  description \"Main St 12\"\n" +
+		"\n" +
+		"!\nend"
+	if got := CleanZteBackupOutput(raw); got != want {
+		t.Fatalf("got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readFixture returns testdata/<name>.
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// simulatorFixture returns a Nokia capture served by the simulator.
+func simulatorFixture(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("..", "simulator", "fixtures", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func ptr(f float64) *float64 { return &f }
+
+func floatEq(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// deref makes optional readings printable.
+func deref(f *float64) any {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
@@ -0,0 +1,390 @@
+package extractor
+
+import (
+	"bufio"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FlatConfig is the provisioning held in a Nokia "info configure flat"
+// backup. Every object keeps the settings the typed fields do not cover in
+// Attrs, keyed by their CLI keyword.
+type FlatConfig struct {
+	Slots            []ConfigSlot            `json:"slots"`
+	Onts             []ConfigOnt             `json:"onts"`
+	BridgePorts      []ConfigBridgePort      `json:"bridge_ports"`
+	Vlans            []ConfigVlan            `json:"vlans"`
+	QosProfiles      []ConfigQosProfile      `json:"qos_profiles"`
+	SecurityProfiles []ConfigSecurityProfile `json:"security_profiles"`
+	TrapManagers     []ConfigTrapManager     `json:"trap_managers"`
+}
+
+// ConfigSlot is one "configure equipment slot" entry, e.g. "nt-a" or
+// "lt:1/1/1".
+type ConfigSlot struct {
+	Slot        string            `json:"slot"`
+	PlannedType string            `json:"planned_type"`
+	AdminState  string            `json:"admin_state"`
+	Attrs       map[string]string `json:"attrs"`
+}
+
+// ConfigOnt is one "configure equipment ont interface" entry. Pon is the
+// index without the ONT number. Vlans is filled from the ONT's bridge ports.
+type ConfigOnt struct {
+	OntIdx     string            `json:"ont_idx"`
+	Pon        string            `json:"pon"`
+	Sernum     string            `json:"sernum"`
+	Desc1      string            `json:"desc1"`
+	Desc2      string            `json:"desc2"`
+	AdminState string            `json:"admin_state"`
+	Vlans      []int             `json:"vlans"`
+	Attrs      map[string]string `json:"attrs"`
+}
+
+// ConfigBridgePort is one "configure bridge port" entry. OntIdx is set for
+// ONT UNI ports (rack/shelf/slot/port/ont/card/port).
+type ConfigBridgePort struct {
+	Port          string             `json:"port"`
+	OntIdx        string             `json:"ont_idx,omitempty"`
+	MaxUnicastMac int                `json:"max_unicast_mac"`
+	Pvid          int                `json:"pvid,omitempty"`
+	Vlans         []ConfigBridgeVlan `json:"vlans"`
+	Attrs         map[string]string  `json:"attrs"`
+}
+
+type ConfigBridgeVlan struct {
+	ID    int               `json:"id"`
+	Tag   string            `json:"tag"`
+	Attrs map[string]string `json:"attrs"`
+}
+
+type ConfigVlan struct {
+	ID    int               `json:"id"`
+	Mode  string            `json:"mode"`
+	Name  string            `json:"name"`
+	Attrs map[string]string `json:"attrs"`
+}
+
+// ConfigQosProfile is one "configure qos profiles <kind> <name>" entry.
+// Settings holds each line's words after the name, e.g. "red:24:48:80" for
+// a queue profile.
+type ConfigQosProfile struct {
+	Kind     string   `json:"kind"`
+	Name     string   `json:"name"`
+	Settings []string `json:"settings"`
+}
+
+// ConfigSecurityProfile is one "configure system security profile" entry.
+type ConfigSecurityProfile struct {
+	Name  string            `json:"name"`
+	Attrs map[string]string `json:"attrs"`
+}
+
+// ConfigTrapManager is one "configure trap manager" destination.
+type ConfigTrapManager struct {
+	Address string            `json:"address"`
+	Attrs   map[string]string `json:"attrs"`
+}
+
+// ParseFlatConfig builds a FlatConfig from "info configure flat" output.
+// An object spread over several lines is merged into one entry, in the
+// order it first appears. Lines for other parts of the config are skipped.
+func ParseFlatConfig(output string) *FlatConfig {
+	output = strings.ToValidUTF8(output, "")
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+
+	p := newFlatParser()
+	sc := bufio.NewScanner(strings.NewReader(output))
+	sc.Buffer(make([]byte, 1024), 1024*1024)
+	for sc.Scan() {
+		words := splitWords(sc.Text())
+		if len(words) < 2 || words[0] != "configure" {
+			continue
+		}
+		p.line(words[1:])
+	}
+	return p.finish()
+}
+
+// flatParser indexes each kind of object by its key while a config is read.
+type flatParser struct {
+	cfg      FlatConfig
+	slots    map[string]int
+	onts     map[string]int
+	ports    map[string]int
+	vlans    map[int]int
+	qos      map[string]int
+	security map[string]int
+	traps    map[string]int
+}
+
+func newFlatParser() *flatParser {
+	return &flatParser{
+		slots:    map[string]int{},
+		onts:     map[string]int{},
+		ports:    map[string]int{},
+		vlans:    map[int]int{},
+		qos:      map[string]int{},
+		security: map[string]int{},
+		traps:    map[string]int{},
+	}
+}
+
+func (p *flatParser) line(w []string) {
+	switch {
+	case hasWords(w, "equipment", "slot") && len(w) > 2:
+		p.slot(w[2], w[3:])
+	case hasWords(w, "equipment", "ont", "interface") && len(w) > 3:
+		p.ont(w[3], w[4:])
+	case hasWords(w, "bridge", "port") && len(w) > 2:
+		p.bridgePort(w[2], w[3:])
+	case hasWords(w, "vlan", "id") && len(w) > 2:
+		p.vlan(w[2], w[3:])
+	case hasWords(w, "qos", "profiles") && len(w) > 3:
+		p.qosProfile(w[2], w[3], w[4:])
+	case hasWords(w, "system", "security", "profile") && len(w) > 3:
+		p.securityProfile(w[3], w[4:])
+	case hasWords(w, "trap", "manager") && len(w) > 2:
+		p.trapManager(w[2], w[3:])
+	}
+}
+
+func (p *flatParser) slot(id string, w []string) {
+	i, ok := p.slots[id]
+	if !ok {
+		i = len(p.cfg.Slots)
+		p.slots[id] = i
+		p.cfg.Slots = append(p.cfg.Slots, ConfigSlot{Slot: id, Attrs: map[string]string{}})
+	}
+	s := &p.cfg.Slots[i]
+	for k, v := range pairs(w) {
+		switch k {
+		case "planned-type":
+			s.PlannedType = v
+		case "admin-state":
+			s.AdminState = v
+		default:
+			s.Attrs[k] = v
+		}
+	}
+}
+
+func (p *flatParser) ont(idx string, w []string) {
+	i, ok := p.onts[idx]
+	if !ok {
+		i = len(p.cfg.Onts)
+		p.onts[idx] = i
+		pon := idx
+		if j := strings.LastIndexByte(idx, '/'); j >= 0 {
+			pon = idx[:j]
+		}
+		p.cfg.Onts = append(p.cfg.Onts, ConfigOnt{OntIdx: idx, Pon: pon, Attrs: map[string]string{}})
+	}
+	o := &p.cfg.Onts[i]
+	for k, v := range pairs(w) {
+		switch k {
+		case "sernum":
+			o.Sernum = v
+		case "desc1":
+			o.Desc1 = v
+		case "desc2":
+			o.Desc2 = v
+		case "admin-state":
+			o.AdminState = v
+		default:
+			o.Attrs[k] = v
+		}
+	}
+}
+
+func (p *flatParser) bridgePort(port string, w []string) {
+	i, ok := p.ports[port]
+	if !ok {
+		i = len(p.cfg.BridgePorts)
+		p.ports[port] = i
+		bp := ConfigBridgePort{Port: port, Attrs: map[string]string{}}
+		// rack/shelf/slot/port/ont/card/port
+		if parts := strings.Split(port, "/"); len(parts) == 7 {
+			bp.OntIdx = strings.Join(parts[:5], "/")
+		}
+		p.cfg.BridgePorts = append(p.cfg.BridgePorts, bp)
+	}
+	bp := &p.cfg.BridgePorts[i]
+
+	// "vlan-id <id> ..." settings belong to that VLAN on the port
+	if len(w) >= 2 && w[0] == "vlan-id" {
+		id := atoi(w[1])
+		j := slices.IndexFunc(bp.Vlans, func(v ConfigBridgeVlan) bool { return v.ID == id })
+		if j < 0 {
+			j = len(bp.Vlans)
+			bp.Vlans = append(bp.Vlans, ConfigBridgeVlan{ID: id, Attrs: map[string]string{}})
+		}
+		v := &bp.Vlans[j]
+		for k, val := range pairs(w[2:]) {
+			if k == "tag" {
+				v.Tag = val
+			} else {
+				v.Attrs[k] = val
+			}
+		}
+		return
+	}
+	for k, v := range pairs(w) {
+		switch k {
+		case "max-unicast-mac":
+			bp.MaxUnicastMac = atoi(v)
+		case "pvid":
+			bp.Pvid = atoi(v)
+		default:
+			bp.Attrs[k] = v
+		}
+	}
+}
+
+func (p *flatParser) vlan(id string, w []string) {
+	n := atoi(id)
+	i, ok := p.vlans[n]
+	if !ok {
+		i = len(p.cfg.Vlans)
+		p.vlans[n] = i
+		p.cfg.Vlans = append(p.cfg.Vlans, ConfigVlan{ID: n, Attrs: map[string]string{}})
+	}
+	v := &p.cfg.Vlans[i]
+	for k, val := range pairs(w) {
+		switch k {
+		case "mode":
+			v.Mode = val
+		case "name":
+			v.Name = val
+		default:
+			v.Attrs[k] = val
+		}
+	}
+}
+
+func (p *flatParser) qosProfile(kind, name string, w []string) {
+	key := kind + " " + name
+	i, ok := p.qos[key]
+	if !ok {
+		i = len(p.cfg.QosProfiles)
+		p.qos[key] = i
+		p.cfg.QosProfiles = append(p.cfg.QosProfiles, ConfigQosProfile{Kind: kind, Name: name})
+	}
+	if len(w) > 0 {
+		q := &p.cfg.QosProfiles[i]
+		q.Settings = append(q.Settings, strings.Join(w, " "))
+	}
+}
+
+func (p *flatParser) securityProfile(name string, w []string) {
+	i, ok := p.security[name]
+	if !ok {
+		i = len(p.cfg.SecurityProfiles)
+		p.security[name] = i
+		p.cfg.SecurityProfiles = append(p.cfg.SecurityProfiles, ConfigSecurityProfile{Name: name, Attrs: map[string]string{}})
+	}
+	for k, v := range pairs(w) {
+		p.cfg.SecurityProfiles[i].Attrs[k] = v
+	}
+}
+
+func (p *flatParser) trapManager(addr string, w []string) {
+	i, ok := p.traps[addr]
+	if !ok {
+		i = len(p.cfg.TrapManagers)
+		p.traps[addr] = i
+		p.cfg.TrapManagers = append(p.cfg.TrapManagers, ConfigTrapManager{Address: addr, Attrs: map[string]string{}})
+	}
+	for k, v := range pairs(w) {
+		p.cfg.TrapManagers[i].Attrs[k] = v
+	}
+}
+
+// finish fills each ONT's VLANs from its bridge ports.
+func (p *flatParser) finish() *FlatConfig {
+	for _, bp := range p.cfg.BridgePorts {
+		i, ok := p.onts[bp.OntIdx]
+		if !ok {
+			continue
+		}
+		o := &p.cfg.Onts[i]
+		for _, v := range bp.Vlans {
+			if !slices.Contains(o.Vlans, v.ID) {
+				o.Vlans = append(o.Vlans, v.ID)
+			}
+		}
+	}
+	return &p.cfg
+}
+
+// adminFlags are the keywords that stand alone for an admin state.
+var adminFlags = map[string]bool{"lock": true, "unlock": true}
+
+// pairs reads "keyword value" settings. "lock" and "unlock" stand alone and
+// are returned as admin-state; a trailing keyword gets an empty value.
+func pairs(w []string) map[string]string {
+	out := map[string]string{}
+	for i := 0; i < len(w); i++ {
+		if adminFlags[w[i]] {
+			out["admin-state"] = w[i]
+			continue
+		}
+		if i+1 < len(w) {
+			out[w[i]] = w[i+1]
+			i++
+			continue
+		}
+		out[w[i]] = ""
+	}
+	return out
+}
+
+// splitWords splits a config line on blanks, keeping double-quoted strings
+// (with \" escapes) as one word without their quotes.
+func splitWords(line string) []string {
+	var out []string
+	var cur strings.Builder
+	inWord, quoted := false, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(line):
+			i++
+			cur.WriteByte(line[i])
+		case c == '"':
+			quoted = !quoted
+			inWord = true
+		case !quoted && (c == ' ' || c == '\t'):
+			if inWord {
+				out = append(out, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		out = append(out, cur.String())
+	}
+	return out
+}
+
+func hasWords(w []string, prefix ...string) bool {
+	if len(w) < len(prefix) {
+		return false
+	}
+	for i, p := range prefix {
+		if w[i] != p {
+			return false
+		}
+	}
+	return true
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
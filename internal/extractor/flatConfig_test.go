@@ -0,0 +1,91 @@
+package extractor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFlatConfig(t *testing.T) {
+	cfg := ParseFlatConfig(simulatorFixture(t, "info-configure-flat.txt"))
+
+	if len(cfg.Slots) != 4 {
+		t.Fatalf("got %d slots, want 4", len(cfg.Slots))
+	}
+	lt := cfg.Slots[2]
+	if lt.Slot != "lt:1/1/1" || lt.PlannedType != "fglt-d" || lt.AdminState != "unlock" || lt.Attrs["operational-mode"] != "gpon" {
+		t.Errorf("slot = %+v", lt)
+	}
+
+	if len(cfg.Onts) != 6 {
+		t.Fatalf("got %d ONTs, want 6", len(cfg.Onts))
+	}
+	ont := cfg.Onts[0]
+	want := ConfigOnt{
+		OntIdx: "1/1/1/1/1", Pon: "1/1/1/1", Sernum: "HWTC:98083F70",
+		Desc1: "N-221-1-2", Desc2: "customer-1", AdminState: "up", Vlans: []int{150},
+	}
+	if ont.OntIdx != want.OntIdx || ont.Pon != want.Pon || ont.Sernum != want.Sernum ||
+		ont.Desc1 != want.Desc1 || ont.Desc2 != want.Desc2 || ont.AdminState != want.AdminState ||
+		!reflect.DeepEqual(ont.Vlans, want.Vlans) {
+		t.Errorf("ONT = %+v\nwant %+v", ont, want)
+	}
+	if ont.Attrs["planned-us-rate"] != "nominal-line-rate" {
+		t.Errorf("attrs = %v", ont.Attrs)
+	}
+	if cfg.Onts[5].Vlans != nil {
+		t.Errorf("ONT without bridge ports has VLANs %v", cfg.Onts[5].Vlans)
+	}
+
+	if len(cfg.BridgePorts) != 3 {
+		t.Fatalf("got %d bridge ports, want 3", len(cfg.BridgePorts))
+	}
+	bp := cfg.BridgePorts[1]
+	if bp.Port != "1/1/1/1/2/6/1" || bp.OntIdx != "1/1/1/1/2" || bp.MaxUnicastMac != 8 {
+		t.Errorf("bridge port = %+v", bp)
+	}
+	if len(bp.Vlans) != 1 || bp.Vlans[0].ID != 150 || bp.Vlans[0].Tag != "single-tagged" || bp.Vlans[0].Attrs["qos"] != "priority:0" {
+		t.Errorf("bridge port VLANs = %+v", bp.Vlans)
+	}
+
+	if len(cfg.Vlans) != 2 || cfg.Vlans[1].ID != 910 || cfg.Vlans[1].Mode != "residential-bridge" || cfg.Vlans[1].Name != "IE-Internet-Vlan-910" {
+		t.Errorf("VLANs = %+v", cfg.Vlans)
+	}
+
+	wantQos := []ConfigQosProfile{
+		{Kind: "queue", Name: "FD_BEQ", Settings: []string{"red:24:48:80"}},
+		{Kind: "queue", Name: "FD_CLQ", Settings: []string{"red:24:48:80"}},
+		{Kind: "queue", Name: "BACKPLQ", Settings: []string{"threecolour-taildrop:48:40:32"}},
+	}
+	if !reflect.DeepEqual(cfg.QosProfiles, wantQos) {
+		t.Errorf("QoS profiles = %+v", cfg.QosProfiles)
+	}
+}
+
+func TestParseFlatConfigMerges(t *testing.T) {
+	cfg := ParseFlatConfig("configure equipment ont interface 1/1/2/3/4 desc1 \"say \\\"hi\\\"\" lock\r\n" +
+		"configure bridge port 1/1/2/3/4/14/1 vlan-id 100 tag untagged\r\n" +
+		"configure bridge port 1/1/2/3/4/14/1 vlan-id 100 l2fwder-vlan 2100\r\n" +
+		"configure bridge port 1/1/2/3/4/14/1 pvid 100\r\n" +
+		"configure system security profile admin prompt %s%d terminal-timeout 30\r\n" +
+		"configure trap manager 10.0.0.5:162 priority major\r\n" +
+		"configure equipment ont interface 1/1/2/3/4 sernum ALCL:B3C4D5E6\r\n")
+
+	if len(cfg.Onts) != 1 {
+		t.Fatalf("got %d ONTs, want 1", len(cfg.Onts))
+	}
+	o := cfg.Onts[0]
+	if o.Desc1 != `say "hi"` || o.AdminState != "lock" || o.Sernum != "ALCL:B3C4D5E6" || !reflect.DeepEqual(o.Vlans, []int{100}) {
+		t.Errorf("ONT = %+v", o)
+	}
+
+	bp := cfg.BridgePorts[0]
+	if bp.Pvid != 100 || len(bp.Vlans) != 1 || bp.Vlans[0].Tag != "untagged" || bp.Vlans[0].Attrs["l2fwder-vlan"] != "2100" {
+		t.Errorf("bridge port = %+v", bp)
+	}
+	if sp := cfg.SecurityProfiles; len(sp) != 1 || sp[0].Name != "admin" || sp[0].Attrs["terminal-timeout"] != "30" {
+		t.Errorf("security profiles = %+v", sp)
+	}
+	if tm := cfg.TrapManagers; len(tm) != 1 || tm[0].Address != "10.0.0.5:162" || tm[0].Attrs["priority"] != "major" {
+		t.Errorf("trap managers = %+v", tm)
+	}
+}
@@ -0,0 +1,48 @@
+package extractor
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// NormalizeSerial writes an ONT serial the way Nokia shows it, e.g.
+// "HWTC:592360CF", from that form, "HWTC592360CF" or the hex form
+// "48575443592360CF". A partial serial is normalized as far as it goes, so
+// "hwtc5923" becomes "HWTC:5923".
+func NormalizeSerial(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if strings.Contains(s, ":") {
+		return s
+	}
+	if len(s) == 16 {
+		if b, err := hex.DecodeString(s[:8]); err == nil && isVendorID(string(b)) {
+			return string(b) + ":" + s[8:]
+		}
+	}
+	if len(s) > 4 && isVendorID(s[:4]) {
+		return s[:4] + ":" + s[4:]
+	}
+	return s
+}
+
+// SerialVendor returns the vendor ID of a serial, e.g. "HWTC" for
+// "HWTC:592360CF", or "" when it has none.
+func SerialVendor(s string) string {
+	s = NormalizeSerial(s)
+	if i := strings.IndexByte(s, ':'); i > 0 {
+		return s[:i]
+	}
+	return ""
+}
+
+func isVendorID(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package extractor
+
+import "testing"
+
+func TestNormalizeSerial(t *testing.T) {
+	tests := []struct {
+		in, want, vendor string
+	}{
+		{"HWTC:592360CF", "HWTC:592360CF", "HWTC"},
+		{"hwtc:592360cf", "HWTC:592360CF", "HWTC"},
+		{"HWTC592360CF", "HWTC:592360CF", "HWTC"},
+		{"  48575443592360CF ", "HWTC:592360CF", "HWTC"},
+		{"414C434CB3C4D5E6", "ALCL:B3C4D5E6", "ALCL"},
+		{"ZTEGC0A1B2C3", "ZTEG:C0A1B2C3", "ZTEG"},
+		{"hwtc5923", "HWTC:5923", "HWTC"},
+		{"ABCD1234ABCD1234", "ABCD:1234ABCD1234", "ABCD"},
+		{"HWTC", "HWTC", ""},
+		{"5923", "5923", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeSerial(tt.in); got != tt.want {
+			t.Errorf("NormalizeSerial(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := SerialVendor(tt.in); got != tt.vendor {
+			t.Errorf("SerialVendor(%q) = %q, want %q", tt.in, got, tt.vendor)
+		}
+	}
+}
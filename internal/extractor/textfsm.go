@@ -0,0 +1,273 @@
+package extractor
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirikothe/gotextfsm"
+)
+
+// Record is one row parsed by a TextFSM template. Values are a string, or a
+// []string for List values.
+type Record map[string]any
+
+// String returns the value of key, the first element for lists.
+func (r Record) String(key string) string {
+	switch v := r[key].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []string:
+		if len(v) > 0 {
+			return strings.TrimSpace(v[0])
+		}
+	}
+	return ""
+}
+
+func (r Record) Int(key string) int {
+	n, _ := strconv.Atoi(r.String(key))
+	return n
+}
+
+// Float returns the value of key as a number, or nil when it is missing or
+// not numeric.
+func (r Record) Float(key string) *float64 {
+	v, ok := parseFloat(r.String(key))
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+// TemplateSet holds TextFSM templates by vendor, firmware and command,
+// loaded from a directory laid out as
+//
+//	<vendor>/<command>.textfsm             for every firmware
+//	<vendor>/<firmware>/<command>.textfsm  for firmware releases starting with <firmware>
+//
+// where <command> is the command with its words joined by dashes, e.g.
+// nokia/6.2/show-equipment-ont-optics.textfsm. The longest matching
+// firmware wins, then the vendor default.
+type TemplateSet struct {
+	// templates maps vendor -> firmware ("" for the default) -> command.
+	// The source is kept rather than the compiled FSM because gotextfsm
+	// keeps parse state in it.
+	templates map[string]map[string]map[string]string
+}
+
+// LoadTemplates reads and checks every *.textfsm file under dir.
+func LoadTemplates(dir string) (*TemplateSet, error) {
+	ts := &TemplateSet{templates: map[string]map[string]map[string]string{}}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".textfsm" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		var vendor, firmware string
+		switch len(parts) {
+		case 2:
+			vendor = parts[0]
+		case 3:
+			vendor, firmware = parts[0], parts[1]
+		default:
+			return fmt.Errorf("extractor: template %s: want <vendor>/[<firmware>/]<command>.textfsm", rel)
+		}
+		command := strings.TrimSuffix(parts[len(parts)-1], ".textfsm")
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var fsm gotextfsm.TextFSM
+		if err := fsm.ParseString(string(b)); err != nil {
+			return fmt.Errorf("extractor: template %s: %w", rel, err)
+		}
+
+		vendor = strings.ToLower(vendor)
+		if ts.templates[vendor] == nil {
+			ts.templates[vendor] = map[string]map[string]string{}
+		}
+		if ts.templates[vendor][firmware] == nil {
+			ts.templates[vendor][firmware] = map[string]string{}
+		}
+		ts.templates[vendor][firmware][command] = string(b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+// lookup finds the template for command on vendor at firmware.
+func (ts *TemplateSet) lookup(vendor, firmware, command string) (string, bool) {
+	if ts == nil {
+		return "", false
+	}
+	byFirmware := ts.templates[strings.ToLower(vendor)]
+	name := strings.Join(strings.Fields(command), "-")
+
+	best, found := -1, ""
+	for fw, cmds := range byFirmware {
+		src, ok := cmds[name]
+		if !ok || !strings.HasPrefix(firmware, fw) || len(fw) <= best {
+			continue
+		}
+		best, found = len(fw), src
+	}
+	return found, best >= 0
+}
+
+// Parse runs the template for command on output. ok is false when there is
+// no template for it, so the caller can fall back to a built-in extractor.
+func (ts *TemplateSet) Parse(vendor, firmware, command, output string) (recs []Record, ok bool, err error) {
+	src, ok := ts.lookup(vendor, firmware, command)
+	if !ok {
+		return nil, false, nil
+	}
+
+	var fsm gotextfsm.TextFSM
+	if err := fsm.ParseString(src); err != nil {
+		return nil, true, err
+	}
+	var out gotextfsm.ParserOutput
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	if err := out.ParseTextString(output, fsm, true); err != nil {
+		return nil, true, fmt.Errorf("extractor: %s: %w", command, err)
+	}
+
+	recs = make([]Record, len(out.Dict))
+	for i, d := range out.Dict {
+		recs[i] = Record(d)
+	}
+	return recs, true, nil
+}
+
+var (
+	templatesMu sync.RWMutex
+	templates   *TemplateSet
+)
+
+// SetTemplates installs the template set used by ParseCommand. nil turns
+// templates off.
+func SetTemplates(ts *TemplateSet) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	templates = ts
+}
+
+// ParseCommand parses output with the installed template set.
+func ParseCommand(vendor, firmware, command, output string) ([]Record, bool, error) {
+	templatesMu.RLock()
+	ts := templates
+	templatesMu.RUnlock()
+	return ts.Parse(vendor, firmware, command, output)
+}
+
+// The functions below build the typed outputs from parsed records. Templates
+// name their values after the fields:
+//
+//	OntPower        ONT_IDX, OLT_RX, and optionally ONT_RX, ONT_TX,
+//	                LASER_BIAS, TEMPERATURE, VOLTAGE
+//	OntDesc         ONT_IDX, DESC1, DESC2
+//	PortProtection  PORT, PORT_STATE, PAIRED_STATE, SWO_REASON, NUM_SWO
+//	CpuLoad         SLOT, AVERAGE
+//	uptime          UPTIME
+//	Temperature     SLOT, SENSOR_ID, ACT_TEMP, TCA_HIGH, SHUT_HIGH
+
+// OntPowerFromRecords skips records without an ONT index or a numeric Rx,
+// e.g. offline ONTs.
+func OntPowerFromRecords(recs []Record) []OntPower {
+	var out []OntPower
+	for _, r := range recs {
+		idx := r.String("ONT_IDX")
+		rx, ok := parseFloat(r.String("OLT_RX"))
+		if idx == "" || !ok {
+			continue
+		}
+		out = append(out, OntPower{
+			OntIdx:      idx,
+			OltRx:       rx,
+			OntRx:       r.Float("ONT_RX"),
+			OntTx:       r.Float("ONT_TX"),
+			LaserBias:   r.Float("LASER_BIAS"),
+			Temperature: r.Float("TEMPERATURE"),
+			Voltage:     r.Float("VOLTAGE"),
+		})
+	}
+	return out
+}
+
+// OntDescFromRecords cleans the descriptions the way ExtractAllDesc does.
+func OntDescFromRecords(recs []Record) []OntDesc {
+	var out []OntDesc
+	for _, r := range recs {
+		idx := r.String("ONT_IDX")
+		if idx == "" {
+			continue
+		}
+		desc1, desc2 := cleanDesc(strings.ToValidUTF8(r.String("DESC1"), ""), strings.ToValidUTF8(r.String("DESC2"), ""))
+		out = append(out, OntDesc{OntIdx: idx, Desc1: desc1, Desc2: desc2})
+	}
+	return out
+}
+
+func PortProtectionFromRecords(recs []Record) []PortProtection {
+	var out []PortProtection
+	for _, r := range recs {
+		port := r.String("PORT")
+		if port == "" {
+			continue
+		}
+		out = append(out, PortProtection{
+			Port:        port,
+			PortState:   r.String("PORT_STATE"),
+			PairedState: r.String("PAIRED_STATE"),
+			SwoReason:   r.String("SWO_REASON"),
+			NumSwo:      r.Int("NUM_SWO"),
+		})
+	}
+	return out
+}
+
+// HealthFromRecords builds Health from the records of the CPU load, uptime
+// and temperature commands.
+func HealthFromRecords(cpu, uptime, temp []Record) Health {
+	var h Health
+	for _, r := range cpu {
+		if slot := r.String("SLOT"); slot != "" {
+			h.CpuLoads = append(h.CpuLoads, CpuLoad{Slot: slot, Average: r.Int("AVERAGE")})
+		}
+	}
+	for _, r := range uptime {
+		if u := r.String("UPTIME"); u != "" {
+			h.Uptime = u
+			break
+		}
+	}
+	for _, r := range temp {
+		if slot := r.String("SLOT"); slot != "" {
+			h.Temperatures = append(h.Temperatures, Temperature{
+				Slot:     slot,
+				SensorID: r.Int("SENSOR_ID"),
+				ActTemp:  r.Int("ACT_TEMP"),
+				TcaHigh:  r.Int("TCA_HIGH"),
+				ShutHigh: r.Int("SHUT_HIGH"),
+			})
+		}
+	}
+	return h
+}
@@ -0,0 +1,119 @@
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestShippedTemplates runs every template under textfsm/ on the simulator
+// capture of its command and expects what the built-in parser makes of it.
+func TestShippedTemplates(t *testing.T) {
+	dir := filepath.Join("..", "..", "textfsm")
+	ts, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builtIn := map[string]func(recs []Record, out string) (got, want any){
+		"show-equipment-ont-optics": func(recs []Record, out string) (any, any) {
+			return OntPowerFromRecords(recs), ExtractAllOntPower(out)
+		},
+		"show-equipment-ont-status-pon": func(recs []Record, out string) (any, any) {
+			return OntDescFromRecords(recs), ExtractAllDesc(out)
+		},
+		"show-port-protection": func(recs []Record, out string) (any, any) {
+			return PortProtectionFromRecords(recs), ExtractPortProtection(out)
+		},
+		"show-system-cpu-load-detail": func(recs []Record, out string) (any, any) {
+			return HealthFromRecords(recs, nil, nil).CpuLoads, ExtractCpuLoads(out)
+		},
+		"show-core1-uptime": func(recs []Record, out string) (any, any) {
+			return HealthFromRecords(nil, recs, nil).Uptime, ExtractUptime(out)
+		},
+		"show-equipment-temperature": func(recs []Record, out string) (any, any) {
+			return HealthFromRecords(nil, nil, recs).Temperatures, ExtractTemperatures(out)
+		},
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "nokia", "*.textfsm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no templates shipped")
+	}
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), ".textfsm")
+		t.Run(name, func(t *testing.T) {
+			compare, ok := builtIn[name]
+			if !ok {
+				t.Fatalf("no built-in parser to compare %s with", name)
+			}
+			out := simulatorFixture(t, name+".txt")
+			recs, ok, err := ts.Parse("nokia", "", strings.ReplaceAll(name, "-", " "), out)
+			if err != nil || !ok {
+				t.Fatalf("parse: ok=%v err=%v", ok, err)
+			}
+			got, want := compare(recs, out)
+			if reflect.ValueOf(want).IsZero() {
+				t.Fatalf("built-in parser found nothing in the fixture")
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("template: %+v\nbuilt-in: %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestOntDescFromRecordsCleans(t *testing.T) {
+	recs := []Record{
+		{"ONT_IDX": "1/1/1/1/1", "DESC1": `"N-221-1-2"`, "DESC2": "cust*omer\t-1\ufffd undefined"},
+		{"ONT_IDX": "1/1/1/1/2", "DESC1": "", "DESC2": `"N-221-3-4   customer-2"`},
+		{"ONT_IDX": "", "DESC1": "orphan"},
+	}
+	want := []OntDesc{
+		{OntIdx: "1/1/1/1/1", Desc1: "N-221-1-2", Desc2: "customer-1"},
+		{OntIdx: "1/1/1/1/2", Desc1: "N-221-3-4", Desc2: "customer-2"},
+	}
+	if got := OntDescFromRecords(recs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestTemplateLookup(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := func(value string) string {
+		return "Value UPTIME (.+)\n\nStart\n  ^" + value + "\\s*:\\s*${UPTIME} -> Record\n"
+	}
+	for path, src := range map[string]string{
+		"nokia/show-core1-uptime.textfsm":       tmpl("Up"),
+		"nokia/6.2/show-core1-uptime.textfsm":   tmpl("Up62"),
+		"nokia/6.2.1/show-core1-uptime.textfsm": tmpl("Up621"),
+	} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ts, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := "Up : a\nUp62 : b\nUp621 : c\n"
+	for firmware, want := range map[string]string{"": "a", "5.9": "a", "6.2.0": "b", "6.2.1a": "c"} {
+		recs, ok, err := ts.Parse("Nokia", firmware, "show  core1-uptime", out)
+		if err != nil || !ok || len(recs) != 1 || recs[0].String("UPTIME") != want {
+			t.Errorf("firmware %q: %v %v %v, want %q", firmware, recs, ok, err, want)
+		}
+	}
+	if _, ok, _ := ts.Parse("huawei", "", "show core1-uptime", out); ok {
+		t.Error("template used for another vendor")
+	}
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/extractor"
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigHandler serves the provisioning parsed from Nokia backups. The
+// latest backup of each host is parsed once and kept until a newer one
+// appears.
+type ConfigHandler struct {
+	Repo repository.BackupRepository
+
+	mu     sync.Mutex
+	parsed map[string]parsedConfig // by host
+}
+
+type parsedConfig struct {
+	backupID uint
+	cfg      *extractor.FlatConfig
+}
+
+func NewConfigHandler(r repository.BackupRepository) *ConfigHandler {
+	return &ConfigHandler{Repo: r, parsed: map[string]parsedConfig{}}
+}
+
+type configResponse struct {
+	Device     string                `json:"device"`
+	Site       string                `json:"site"`
+	Host       string                `json:"host"`
+	BackupID   uint                  `json:"backup_id"`
+	BackedUpAt time.Time             `json:"backed_up_at"`
+	Config     *extractor.FlatConfig `json:"config"`
+}
+
+// configOnt is one ONT from the configs, with the OLT it is provisioned on.
+type configOnt struct {
+	Device   string `json:"device"`
+	Site     string `json:"site"`
+	Host     string `json:"host"`
+	BackupID uint   `json:"backup_id"`
+	extractor.ConfigOnt
+}
+
+// Get returns the parsed config of the host's latest backup.
+func (h *ConfigHandler) Get(c *gin.Context) {
+	b, err := h.Repo.Latest(c.Param("host"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no backup for this host"})
+		return
+	}
+	cfg, err := h.latest(b)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, configResponse{b.Device, b.Site, b.Host, b.ID, b.CreatedAt, cfg})
+}
+
+// GetBackup returns the parsed config of any backup by ID.
+func (h *ConfigHandler) GetBackup(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	b, err := h.Repo.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "backup not found"})
+		return
+	}
+	cfg, err := parseBackup(b)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, configResponse{b.Device, b.Site, b.Host, b.ID, b.CreatedAt, cfg})
+}
+
+// Onts lists the ONTs provisioned in the latest backup of every Nokia OLT.
+// ?host= and ?device= pick OLTs, ?pon= (e.g. 1/1/1/3) a PON, ?vlan= the
+// ONTs with a bridge port in that VLAN and ?sernum= one serial.
+func (h *ConfigHandler) Onts(c *gin.Context) {
+	vlan := 0
+	if v := c.Query("vlan"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "vlan must be a number"})
+			return
+		}
+		vlan = n
+	}
+	host, device := c.Query("host"), c.Query("device")
+	pon, sernum := c.Query("pon"), c.Query("sernum")
+
+	backups, err := h.Repo.LatestPerHost()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	out := []configOnt{}
+	for i := range backups {
+		b := &backups[i]
+		if b.Vendor != "nokia" || (host != "" && b.Host != host) || (device != "" && b.Device != device) {
+			continue
+		}
+		cfg, err := h.latest(b)
+		if err != nil {
+			// a backup whose file is gone should not hide the others
+			continue
+		}
+		for _, o := range cfg.Onts {
+			if pon != "" && o.Pon != pon {
+				continue
+			}
+			if sernum != "" && o.Sernum != sernum {
+				continue
+			}
+			if vlan != 0 && !slices.Contains(o.Vlans, vlan) {
+				continue
+			}
+			out = append(out, configOnt{b.Device, b.Site, b.Host, b.ID, o})
+		}
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// latest parses b, the host's latest backup, reusing the previous parse
+// while it is still the latest.
+func (h *ConfigHandler) latest(b *models.OltBackups) (*extractor.FlatConfig, error) {
+	h.mu.Lock()
+	p, ok := h.parsed[b.Host]
+	h.mu.Unlock()
+	if ok && p.backupID == b.ID {
+		return p.cfg, nil
+	}
+
+	cfg, err := parseBackup(b)
+	if err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	h.parsed[b.Host] = parsedConfig{backupID: b.ID, cfg: cfg}
+	h.mu.Unlock()
+	return cfg, nil
+}
+
+func parseBackup(b *models.OltBackups) (*extractor.FlatConfig, error) {
+	if b.Vendor != "nokia" {
+		return nil, fmt.Errorf("config parsing is only supported for nokia backups, not %s", b.Vendor)
+	}
+	data, err := os.ReadFile(b.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("read backup %d: %w", b.ID, err)
+	}
+	return extractor.ParseFlatConfig(string(data)), nil
+}
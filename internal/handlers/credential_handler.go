@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Flafl/DevOpsCore/internal/credentials"
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// CredentialHandler manages SSH credential sets. Passwords are write-only:
+// they are sealed on the way in and never returned.
+type CredentialHandler struct {
+	Repo  repository.CredentialRepository
+	Store *credentials.Store
+}
+
+func NewCredentialHandler(r repository.CredentialRepository, s *credentials.Store) *CredentialHandler {
+	return &CredentialHandler{Repo: r, Store: s}
+}
+
+func (h *CredentialHandler) List(c *gin.Context) {
+	data, err := h.Repo.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *CredentialHandler) Create(c *gin.Context) {
+	var req struct {
+		Name     string `json:"name" binding:"required"`
+		Scope    string `json:"scope" binding:"required,oneof=default site olt"`
+		Target   string `json:"target"`
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if msg := checkScope(req.Scope, req.Target); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	sealed, err := h.Store.Seal(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt password", "details": err.Error()})
+		return
+	}
+
+	set := &models.CredentialSet{
+		Name:      req.Name,
+		Scope:     req.Scope,
+		Target:    scopeTarget(req.Scope, req.Target),
+		Username:  req.Username,
+		SecretEnc: sealed,
+	}
+	if err := h.Repo.Create(set); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Failed to create credential set", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, set)
+}
+
+func (h *CredentialHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credential set ID"})
+		return
+	}
+	set, err := h.Repo.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req struct {
+		Name     string  `json:"name"`
+		Scope    string  `json:"scope" binding:"omitempty,oneof=default site olt"`
+		Target   *string `json:"target"`
+		Username string  `json:"username"`
+		Password string  `json:"password"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if req.Name != "" {
+		set.Name = req.Name
+	}
+	if req.Scope != "" {
+		set.Scope = req.Scope
+	}
+	if req.Target != nil {
+		set.Target = *req.Target
+	}
+	if msg := checkScope(set.Scope, set.Target); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	set.Target = scopeTarget(set.Scope, set.Target)
+	if req.Username != "" {
+		set.Username = req.Username
+	}
+	if req.Password != "" {
+		sealed, err := h.Store.Seal(req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt password", "details": err.Error()})
+			return
+		}
+		set.SecretEnc = sealed
+	}
+
+	if err := h.Repo.Update(set); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update credential set", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, set)
+}
+
+func (h *CredentialHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credential set ID"})
+		return
+	}
+	if err := h.Repo.Delete(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete credential set"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Credential set deleted successfully"})
+}
+
+func checkScope(scope, target string) string {
+	if scope != models.CredentialScopeDefault && target == "" {
+		return "target is required for scope " + scope
+	}
+	return ""
+}
+
+// scopeTarget clears the target of the default set so only one can exist.
+func scopeTarget(scope, target string) string {
+	if scope == models.CredentialScopeDefault {
+		return ""
+	}
+	return target
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type EventHandler struct {
+	Repo repository.OltEventRepository
+}
+
+func NewEventHandler(r repository.OltEventRepository) *EventHandler {
+	return &EventHandler{Repo: r}
+}
+
+// List returns OLT events, newest first. ?host= and ?kind= filter them,
+// ?since= (RFC 3339) drops older ones and ?limit= caps the count (default
+// 100, max 1000).
+func (h *EventHandler) List(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	f := repository.EventFilter{
+		Host:  c.Query("host"),
+		Kind:  c.Query("kind"),
+		Limit: limit,
+	}
+	if s := c.Query("since"); s != "" {
+		f.Since, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since, want RFC 3339"})
+			return
+		}
+	}
+
+	data, err := h.Repo.List(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
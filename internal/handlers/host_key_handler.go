@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type HostKeyHandler struct {
+	Repo repository.HostKeyRepository
+}
+
+func NewHostKeyHandler(r repository.HostKeyRepository) *HostKeyHandler {
+	return &HostKeyHandler{Repo: r}
+}
+
+// List returns every pinned host key, mismatches first.
+func (h *HostKeyHandler) List(c *gin.Context) {
+	data, err := h.Repo.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// Approve trusts the key an OLT presented after a mismatch, e.g. after a
+// board replacement. The request must repeat the pending fingerprint so an
+// admin cannot approve a key they have not looked at.
+func (h *HostKeyHandler) Approve(c *gin.Context) {
+	var req struct {
+		Fingerprint string `json:"fingerprint" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	k, err := h.Repo.GetByHost(c.Param("host"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if k.Status != models.HostKeyMismatch {
+		c.JSON(http.StatusConflict, gin.H{"error": "No pending host key for this OLT"})
+		return
+	}
+	if req.Fingerprint != k.PendingFingerprint {
+		c.JSON(http.StatusConflict, gin.H{"error": "Fingerprint does not match the pending key", "pending_fingerprint": k.PendingFingerprint})
+		return
+	}
+
+	k.KeyType = k.PendingKeyType
+	k.PublicKey = k.PendingPublicKey
+	k.Fingerprint = k.PendingFingerprint
+	k.Status = models.HostKeyTrusted
+	k.PendingKeyType = ""
+	k.PendingPublicKey = ""
+	k.PendingFingerprint = ""
+	k.MismatchAt = nil
+
+	if err := h.Repo.Save(k); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to approve host key", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, k)
+}
+
+// Delete forgets the pinned key so the next connect pins whatever the OLT
+// presents.
+func (h *HostKeyHandler) Delete(c *gin.Context) {
+	if err := h.Repo.Delete(c.Param("host")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete host key"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Host key deleted successfully"})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type JobReportHandler struct {
+	Repo repository.JobReportRepository
+}
+
+func NewJobReportHandler(r repository.JobReportRepository) *JobReportHandler {
+	return &JobReportHandler{Repo: r}
+}
+
+// List returns recent job runs, newest first. ?job= filters by job name and
+// ?limit= caps the count (default 50, max 500).
+func (h *JobReportHandler) List(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	data, err := h.Repo.List(c.Query("job"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *JobReportHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	rep, err := h.Repo.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+		return
+	}
+	c.JSON(http.StatusOK, rep)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Flafl/DevOpsCore/internal/credentials"
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// JumpHandler manages jump hosts and the routes that chain them per site or
+// per OLT. Jump host passwords are write-only.
+type JumpHandler struct {
+	Repo  repository.JumpRepository
+	Store *credentials.Store
+}
+
+func NewJumpHandler(r repository.JumpRepository, s *credentials.Store) *JumpHandler {
+	return &JumpHandler{Repo: r, Store: s}
+}
+
+func (h *JumpHandler) ListHosts(c *gin.Context) {
+	data, err := h.Repo.ListHosts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *JumpHandler) CreateHost(c *gin.Context) {
+	var req struct {
+		Name     string `json:"name" binding:"required"`
+		Host     string `json:"host" binding:"required"`
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	sealed, err := h.Store.Seal(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt password", "details": err.Error()})
+		return
+	}
+
+	jh := &models.JumpHost{
+		Name:      req.Name,
+		Host:      req.Host,
+		Username:  req.Username,
+		SecretEnc: sealed,
+	}
+	if err := h.Repo.CreateHost(jh); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Failed to create jump host", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, jh)
+}
+
+func (h *JumpHandler) UpdateHost(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid jump host ID"})
+		return
+	}
+	jh, err := h.Repo.GetHost(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req struct {
+		Host     string `json:"host"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if req.Host != "" {
+		jh.Host = req.Host
+	}
+	if req.Username != "" {
+		jh.Username = req.Username
+	}
+	if req.Password != "" {
+		sealed, err := h.Store.Seal(req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt password", "details": err.Error()})
+			return
+		}
+		jh.SecretEnc = sealed
+	}
+
+	if err := h.Repo.UpdateHost(jh); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update jump host", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, jh)
+}
+
+func (h *JumpHandler) DeleteHost(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid jump host ID"})
+		return
+	}
+	if err := h.Repo.DeleteHost(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete jump host"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Jump host deleted successfully"})
+}
+
+func (h *JumpHandler) ListRoutes(c *gin.Context) {
+	data, err := h.Repo.ListRoutes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+type jumpRouteRequest struct {
+	Scope  string   `json:"scope" binding:"required,oneof=site olt"`
+	Target string   `json:"target" binding:"required"`
+	Hops   []string `json:"hops"`
+}
+
+func (h *JumpHandler) CreateRoute(c *gin.Context) {
+	var req jumpRouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if missing := h.unknownHop(req.Hops); missing != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown jump host", "name": missing})
+		return
+	}
+
+	rt := &models.JumpRoute{Scope: req.Scope, Target: req.Target, Hops: tagsSlice(req.Hops)}
+	if err := h.Repo.CreateRoute(rt); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Failed to create jump route", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rt)
+}
+
+func (h *JumpHandler) UpdateRoute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid jump route ID"})
+		return
+	}
+	rt, err := h.Repo.GetRoute(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req jumpRouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if missing := h.unknownHop(req.Hops); missing != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown jump host", "name": missing})
+		return
+	}
+
+	rt.Scope = req.Scope
+	rt.Target = req.Target
+	rt.Hops = tagsSlice(req.Hops)
+	if err := h.Repo.UpdateRoute(rt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update jump route", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rt)
+}
+
+func (h *JumpHandler) DeleteRoute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid jump route ID"})
+		return
+	}
+	if err := h.Repo.DeleteRoute(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete jump route"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Jump route deleted successfully"})
+}
+
+func (h *JumpHandler) unknownHop(names []string) string {
+	for _, n := range names {
+		if _, err := h.Repo.GetHostByName(n); err != nil {
+			return n
+		}
+	}
+	return ""
+}
@@ -0,0 +1,432 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/Flafl/DevOpsCore/internal/shell"
+	"github.com/gin-gonic/gin"
+)
+
+type OltHandler struct {
+	Repo   repository.OltRepository
+	APIURL string
+}
+
+func NewOltHandler(r repository.OltRepository, apiURL string) *OltHandler {
+	return &OltHandler{Repo: r, APIURL: apiURL}
+}
+
+type oltRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	Host         string   `json:"host" binding:"required,hostname_port|ip|hostname_rfc1123"`
+	Site         string   `json:"site"`
+	Vendor       string   `json:"vendor"`
+	Model        string   `json:"model"`
+	Firmware     string   `json:"firmware"`
+	Tags         []string `json:"tags"`
+	Transport    string   `json:"transport" binding:"omitempty,oneof=ssh telnet"`
+	HealthSource string   `json:"health_source" binding:"omitempty,oneof=ssh snmp"`
+	Enabled      *bool    `json:"enabled"`
+}
+
+func (h *OltHandler) List(c *gin.Context) {
+	data, err := h.Repo.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *OltHandler) Create(c *gin.Context) {
+	var req oltRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if req.Vendor != "" && !knownVendor(req.Vendor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown vendor", "vendors": shell.Vendors()})
+		return
+	}
+
+	if existing, _ := h.Repo.GetByHost(req.Host); existing != nil {
+		if existing.DeletedAt.Valid {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "OLT with this host was deleted; restore it with POST /api/admin/olts/import?restore=true",
+			})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "OLT with this host already exists"})
+		return
+	}
+
+	olt := &models.Olt{
+		Name:         req.Name,
+		Host:         req.Host,
+		Site:         req.Site,
+		Vendor:       strings.ToLower(req.Vendor),
+		DeviceModel:  req.Model,
+		Firmware:     req.Firmware,
+		Tags:         tagsSlice(req.Tags),
+		Transport:    req.Transport,
+		HealthSource: req.HealthSource,
+		Enabled:      req.Enabled == nil || *req.Enabled,
+	}
+	if olt.Vendor == "" {
+		olt.Vendor = "nokia"
+	}
+	if olt.Transport == "" {
+		olt.Transport = "ssh"
+	}
+	if olt.HealthSource == "" {
+		olt.HealthSource = "ssh"
+	}
+
+	if err := h.Repo.Create(olt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create OLT", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, olt)
+}
+
+func (h *OltHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OLT ID"})
+		return
+	}
+	olt, err := h.Repo.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req struct {
+		Name         string   `json:"name"`
+		Host         string   `json:"host" binding:"omitempty,hostname_port|ip|hostname_rfc1123"`
+		Site         *string  `json:"site"`
+		Vendor       string   `json:"vendor"`
+		Model        *string  `json:"model"`
+		Firmware     *string  `json:"firmware"`
+		Tags         []string `json:"tags"`
+		Transport    string   `json:"transport" binding:"omitempty,oneof=ssh telnet"`
+		HealthSource string   `json:"health_source" binding:"omitempty,oneof=ssh snmp"`
+		Enabled      *bool    `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if req.Vendor != "" && !knownVendor(req.Vendor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown vendor", "vendors": shell.Vendors()})
+		return
+	}
+
+	if req.Name != "" {
+		olt.Name = req.Name
+	}
+	if req.Host != "" {
+		olt.Host = req.Host
+	}
+	if req.Site != nil {
+		olt.Site = *req.Site
+	}
+	if req.Vendor != "" {
+		olt.Vendor = strings.ToLower(req.Vendor)
+	}
+	if req.Model != nil {
+		olt.DeviceModel = *req.Model
+	}
+	if req.Firmware != nil {
+		olt.Firmware = *req.Firmware
+	}
+	if req.Tags != nil {
+		olt.Tags = tagsSlice(req.Tags)
+	}
+	if req.Transport != "" {
+		olt.Transport = req.Transport
+	}
+	if req.HealthSource != "" {
+		olt.HealthSource = req.HealthSource
+	}
+	if req.Enabled != nil {
+		olt.Enabled = *req.Enabled
+	}
+
+	if err := h.Repo.Update(olt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update OLT", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, olt)
+}
+
+func (h *OltHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OLT ID"})
+		return
+	}
+	if err := h.Repo.Delete(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete OLT"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "OLT deleted successfully"})
+}
+
+// Import bulk loads OLTs from a JSON array or a CSV file. The payload can be
+// sent as a multipart "file" field or as the raw request body; the format is
+// taken from the file extension, the ?format= query or the Content-Type.
+// Deleted OLTs in the file are skipped unless ?restore=true.
+func (h *OltHandler) Import(c *gin.Context) {
+	format := strings.ToLower(c.Query("format"))
+	var restore bool
+	if s := c.Query("restore"); s != "" {
+		var err error
+		restore, err = strconv.ParseBool(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "restore must be true or false"})
+			return
+		}
+	}
+	var body io.Reader = c.Request.Body
+
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot read upload"})
+			return
+		}
+		defer f.Close()
+		body = f
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+		}
+	}
+	if format == "" {
+		if strings.Contains(c.ContentType(), "csv") {
+			format = "csv"
+		} else {
+			format = "json"
+		}
+	}
+
+	var (
+		olts []models.Olt
+		err  error
+	)
+	switch format {
+	case "csv":
+		olts, err = parseOltCSV(body)
+	case "json":
+		olts, err = parseOltJSON(body)
+	default:
+		err = fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import", "details": err.Error()})
+		return
+	}
+
+	created, updated, skipped, err := h.Repo.Upsert(olts, restore)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import OLTs", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"created": created, "updated": updated, "skipped": skipped})
+}
+
+// Sync pulls the external OLT API (OLTS_API_ENV) into the inventory.
+func (h *OltHandler) Sync(c *gin.Context) {
+	olts, err := shell.FetchOLTs(h.APIURL)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	created, updated, skipped, err := h.Repo.Upsert(olts.ToModels(), false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sync OLTs", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"created": created, "updated": updated, "skipped": skipped})
+}
+
+// Reachability lists the circuit breaker state of every OLT contacted since
+// startup, so unreachable devices show up without digging through logs.
+func (h *OltHandler) Reachability(c *gin.Context) {
+	c.JSON(http.StatusOK, shell.DeviceStatuses())
+}
+
+// Sessions reports the session broker: caps, active sessions per device and
+// how many are queued.
+func (h *OltHandler) Sessions(c *gin.Context) {
+	c.JSON(http.StatusOK, shell.SessionStats())
+}
+
+// ResetReachability closes the breaker of one host so the next scan tries
+// it again immediately.
+func (h *OltHandler) ResetReachability(c *gin.Context) {
+	if !shell.ResetBreaker(c.Param("host")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not tracked"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Breaker reset"})
+}
+
+type oltImportRow struct {
+	Name         string   `json:"name"`
+	Ip           string   `json:"ip"`
+	Host         string   `json:"host"`
+	Site         string   `json:"site"`
+	Vendor       string   `json:"vendor"`
+	Model        string   `json:"model"`
+	Firmware     string   `json:"firmware"`
+	Tags         []string `json:"tags"`
+	Transport    string   `json:"transport"`
+	HealthSource string   `json:"health_source"`
+	Enabled      *bool    `json:"enabled"`
+}
+
+func (r oltImportRow) toModel() (models.Olt, error) {
+	host := r.Host
+	if host == "" {
+		host = r.Ip
+	}
+	if host == "" {
+		return models.Olt{}, errors.New("missing host/ip")
+	}
+	// An empty vendor keeps the one of an existing row; Upsert takes a new
+	// row without one as Nokia.
+	if r.Vendor != "" && !knownVendor(r.Vendor) {
+		return models.Olt{}, fmt.Errorf("unknown vendor %q", r.Vendor)
+	}
+	transport := strings.ToLower(r.Transport)
+	if transport != "" && transport != "ssh" && transport != "telnet" {
+		return models.Olt{}, fmt.Errorf("unknown transport %q", r.Transport)
+	}
+	source := strings.ToLower(r.HealthSource)
+	if source != "" && source != "ssh" && source != "snmp" {
+		return models.Olt{}, fmt.Errorf("unknown health source %q", r.HealthSource)
+	}
+	name := r.Name
+	if name == "" {
+		name = host
+	}
+	return models.Olt{
+		Name:         name,
+		Host:         host,
+		Site:         r.Site,
+		Vendor:       strings.ToLower(r.Vendor),
+		DeviceModel:  r.Model,
+		Firmware:     r.Firmware,
+		Tags:         tagsSlice(r.Tags),
+		Transport:    transport,
+		HealthSource: source,
+		Enabled:      r.Enabled == nil || *r.Enabled,
+	}, nil
+}
+
+func parseOltJSON(r io.Reader) ([]models.Olt, error) {
+	var rows []oltImportRow
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, err
+	}
+	out := make([]models.Olt, 0, len(rows))
+	for i, row := range rows {
+		m, err := row.toModel()
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// parseOltCSV reads a header row followed by one OLT per line. Recognised
+// columns are name, ip (or host), site, vendor, model, firmware, tags
+// (separated by ';'), transport, health_source and enabled.
+func parseOltCSV(r io.Reader) ([]models.Olt, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	col := make(map[string]int, len(header))
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	get := func(rec []string, name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	var out []models.Olt
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		row := oltImportRow{
+			Name:         get(rec, "name"),
+			Ip:           get(rec, "ip"),
+			Host:         get(rec, "host"),
+			Site:         get(rec, "site"),
+			Vendor:       get(rec, "vendor"),
+			Model:        get(rec, "model"),
+			Firmware:     get(rec, "firmware"),
+			Transport:    get(rec, "transport"),
+			HealthSource: get(rec, "health_source"),
+		}
+		if tags := get(rec, "tags"); tags != "" {
+			row.Tags = strings.Split(tags, ";")
+		}
+		if v := get(rec, "enabled"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: enabled: %w", line, err)
+			}
+			row.Enabled = &b
+		}
+
+		m, err := row.toModel()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func knownVendor(v string) bool {
+	_, ok := shell.CanonicalVendor(v)
+	return ok
+}
+
+func tagsSlice(tags []string) models.JSONSlice {
+	out := make(models.JSONSlice, 0, len(tags))
+	for _, t := range tags {
+		if t = strings.TrimSpace(t); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
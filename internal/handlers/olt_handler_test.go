@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// oltRepo keeps OLTs by host, soft-deleted ones included.
+type oltRepo struct {
+	repository.OltRepository
+	byHost map[string]*models.Olt
+}
+
+func (r *oltRepo) GetByHost(host string) (*models.Olt, error) {
+	if o, ok := r.byHost[host]; ok {
+		return o, nil
+	}
+	return nil, errors.New("olt not found")
+}
+
+func (r *oltRepo) Create(o *models.Olt) error {
+	r.byHost[o.Host] = o
+	return nil
+}
+
+func postOlt(h *OltHandler, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/olts", h.Create)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/olts", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateOltHosts(t *testing.T) {
+	deleted := &models.Olt{Host: "10.0.0.9"}
+	deleted.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	repo := &oltRepo{byHost: map[string]*models.Olt{
+		"10.0.0.1": {Host: "10.0.0.1"},
+		"10.0.0.9": deleted,
+	}}
+	h := NewOltHandler(repo, "")
+
+	tests := []struct {
+		host string
+		code int
+		body string
+	}{
+		{"10.0.0.2", http.StatusCreated, ""},
+		{"10.0.0.3:2222", http.StatusCreated, ""},
+		{"olt-3.site.example", http.StatusCreated, ""},
+		{"olt-4.site.example:23", http.StatusCreated, ""},
+		{"not a host", http.StatusBadRequest, ""},
+		{"10.0.0.1", http.StatusConflict, "already exists"},
+		{"10.0.0.9", http.StatusConflict, "restore=true"},
+	}
+	for _, tt := range tests {
+		w := postOlt(h, `{"name":"olt","host":"`+tt.host+`"}`)
+		if w.Code != tt.code {
+			t.Errorf("%s: status %d, want %d (%s)", tt.host, w.Code, tt.code, w.Body)
+			continue
+		}
+		if !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("%s: body %s, want %q", tt.host, w.Body, tt.body)
+		}
+	}
+	if repo.byHost["10.0.0.9"] != deleted {
+		t.Error("deleted OLT was overwritten")
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Flafl/DevOpsCore/internal/extractor"
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type OntSerialHandler struct {
+	Repo repository.OntSerialRepository
+}
+
+func NewOntSerialHandler(r repository.OntSerialRepository) *OntSerialHandler {
+	return &OntSerialHandler{Repo: r}
+}
+
+// Search finds where ONT serials are. ?serial= is a serial or its start, as
+// "HWTC:592360CF", "HWTC592360CF" or hex; ?vendor= keeps vendor IDs
+// (comma separated, e.g. HWTC,BDCM,ALCL). At least one is required. ?limit=
+// caps the count (default 100, max 1000).
+func (h *OntSerialHandler) Search(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	f := repository.SerialFilter{
+		Prefix: extractor.NormalizeSerial(c.Query("serial")),
+		Limit:  limit,
+	}
+	for _, v := range strings.Split(c.Query("vendor"), ",") {
+		if v = strings.ToUpper(strings.TrimSpace(v)); v != "" {
+			f.Vendors = append(f.Vendors, v)
+		}
+	}
+	if f.Prefix == "" && len(f.Vendors) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "serial or vendor is required"})
+		return
+	}
+
+	data, err := h.Repo.Search(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
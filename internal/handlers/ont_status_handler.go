@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type OntStatusHandler struct {
+	Repo repository.OntStatusRepository
+}
+
+func NewOntStatusHandler(r repository.OntStatusRepository) *OntStatusHandler {
+	return &OntStatusHandler{Repo: r}
+}
+
+// List returns ONT states by host and index. ?device=, ?host=, ?admin= and
+// ?oper= match exactly, ?distance_above= keeps ONTs farther than that many
+// km, ?serial_mismatch=true keeps ONTs whose serial changed, ?search= matches
+// the index, serial and descriptions, and ?limit= caps the count (default
+// 1000, max 10000).
+func (h *OntStatusHandler) List(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "1000"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	if limit > 10000 {
+		limit = 10000
+	}
+
+	f := repository.OntStatusFilter{
+		Device:      c.Query("device"),
+		Host:        c.Query("host"),
+		AdminStatus: c.Query("admin"),
+		OperStatus:  c.Query("oper"),
+		Search:      c.Query("search"),
+		Limit:       limit,
+	}
+	if f.DistanceAbove, err = floatQuery(c, "distance_above"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if s := c.Query("serial_mismatch"); s != "" {
+		f.SerialMismatch, err = strconv.ParseBool(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "serial_mismatch must be true or false"})
+			return
+		}
+	}
+
+	data, err := h.Repo.List(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// AcceptSerial takes the ONT's current serial as the expected one, e.g.
+// after a planned swap.
+func (h *OntStatusHandler) AcceptSerial(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ONT status ID"})
+		return
+	}
+	if _, err := h.Repo.GetByID(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.Repo.AcceptSerial(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s, err := h.Repo.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -16,11 +17,28 @@ func NewPowerHandler(powerRepo repository.PowerRepository) *PowerHandler {
 	return &PowerHandler{PowerRepo: powerRepo}
 }
 
+// GetAll pages through the readings, filtered by device, search and the
+// olt_rx_below, ont_rx_below and temperature_above thresholds.
 func (h *PowerHandler) GetAll(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "50"))
-	device := c.Query("device")
-	search := c.Query("search")
+	f := repository.PowerFilter{
+		Device: c.Query("device"),
+		Search: c.Query("search"),
+	}
+	var err error
+	if f.OltRxBelow, err = floatQuery(c, "olt_rx_below"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if f.OntRxBelow, err = floatQuery(c, "ont_rx_below"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if f.TemperatureAbove, err = floatQuery(c, "temperature_above"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	if page < 1 {
 		page = 1
@@ -29,7 +47,7 @@ func (h *PowerHandler) GetAll(c *gin.Context) {
 		perPage = 50
 	}
 
-	data, err := h.PowerRepo.GetPaginated(page, perPage, device, search)
+	data, err := h.PowerRepo.GetPaginated(page, perPage, f)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -75,3 +93,16 @@ func (h *PowerHandler) GetDevices(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, data)
 }
+
+// floatQuery reads an optional numeric query parameter.
+func floatQuery(c *gin.Context, name string) (*float64, error) {
+	q := c.Query(name)
+	if q == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(q, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a number", name)
+	}
+	return &v, nil
+}
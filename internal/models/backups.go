@@ -7,5 +7,6 @@ type OltBackups struct {
 	Device   string `gorm:"index;not null" json:"device"`
 	Site     string `gorm:"index;not null" json:"site"`
 	Host     string `gorm:"index;not null" json:"host"`
+	Vendor   string `gorm:"index;size:20;default:nokia" json:"vendor"`
 	FilePath string `gorm:"not null" json:"file_path"`
 }
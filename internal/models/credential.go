@@ -0,0 +1,22 @@
+package models
+
+import "gorm.io/gorm"
+
+// Credential scopes, from most to least specific. An "olt" set targets one
+// host, a "site" set every OLT of a site and the "default" set everything else.
+const (
+	CredentialScopeOLT     = "olt"
+	CredentialScopeSite    = "site"
+	CredentialScopeDefault = "default"
+)
+
+// CredentialSet is an SSH account for a group of OLTs. The password is only
+// ever stored encrypted and is never serialised.
+type CredentialSet struct {
+	gorm.Model
+	Name      string `gorm:"uniqueIndex;not null;size:100" json:"name"`
+	Scope     string `gorm:"uniqueIndex:idx_credential_scope_target;not null;size:10" json:"scope"`
+	Target    string `gorm:"uniqueIndex:idx_credential_scope_target;size:100" json:"target"`
+	Username  string `gorm:"not null;size:100" json:"username"`
+	SecretEnc string `gorm:"not null" json:"-"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Host key states. A key is trusted once pinned; a different key presented
+// later is kept as pending until an admin approves it.
+const (
+	HostKeyTrusted  = "trusted"
+	HostKeyMismatch = "mismatch"
+)
+
+// HostKey is the SSH host key pinned for one OLT on first connect.
+type HostKey struct {
+	gorm.Model
+	Host        string `gorm:"uniqueIndex;not null" json:"host"`
+	KeyType     string `gorm:"size:50" json:"key_type"`
+	PublicKey   string `gorm:"type:text" json:"public_key"`
+	Fingerprint string `gorm:"size:100" json:"fingerprint"`
+	Status      string `gorm:"size:20;default:trusted" json:"status"`
+
+	PendingKeyType     string     `gorm:"size:50" json:"pending_key_type,omitempty"`
+	PendingPublicKey   string     `gorm:"type:text" json:"pending_public_key,omitempty"`
+	PendingFingerprint string     `gorm:"size:100" json:"pending_fingerprint,omitempty"`
+	MismatchAt         *time.Time `json:"mismatch_at,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// JobReport summarises one run of a scheduled job. Empty counts devices that
+// answered without error but yielded no rows, so an empty PON can be told
+// apart from a command the OLT rejected.
+type JobReport struct {
+	gorm.Model
+	Job        string    `gorm:"index;not null" json:"job"`
+	StartedAt  time.Time `gorm:"index" json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Devices    int       `json:"devices"`
+	Succeeded  int       `json:"succeeded"`
+	Empty      int       `json:"empty"`
+	Failed     int       `json:"failed"`
+	Errors     JSONSlice `gorm:"type:jsonb" json:"errors"`
+}
+
+// JobError is one entry of JobReport.Errors. Kind is "syntax",
+// "not_permitted", "resource_missing", "session", or "storage" when the
+// output could not be saved.
+type JobError struct {
+	Host    string `json:"host"`
+	Device  string `json:"device"`
+	Command string `json:"command,omitempty"`
+	Kind    string `json:"kind"`
+	Error   string `json:"error"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "gorm.io/gorm"
+
+// JumpHost is an SSH bastion used to reach OLT management networks. Its host
+// key is pinned like any OLT's and its password is stored encrypted.
+type JumpHost struct {
+	gorm.Model
+	Name      string `gorm:"uniqueIndex;not null;size:100" json:"name"`
+	Host      string `gorm:"not null" json:"host"`
+	Username  string `gorm:"not null;size:100" json:"username"`
+	SecretEnc string `gorm:"not null" json:"-"`
+}
+
+// JumpRoute is the ordered chain of jump hosts for a site or a single OLT.
+// Scope reuses the credential scopes "site" and "olt"; an OLT route wins
+// over its site's, and an OLT route without hops connects directly.
+type JumpRoute struct {
+	gorm.Model
+	Scope  string    `gorm:"uniqueIndex:idx_jump_route_scope_target;not null;size:10" json:"scope"`
+	Target string    `gorm:"uniqueIndex:idx_jump_route_scope_target;not null;size:100" json:"target"`
+	Hops   JSONSlice `gorm:"type:jsonb" json:"hops"`
+}
+
+// HopNames returns the jump host names of the route in dialling order.
+func (r JumpRoute) HopNames() []string {
+	out := make([]string, 0, len(r.Hops))
+	for _, h := range r.Hops {
+		if s, ok := h.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
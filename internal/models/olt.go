@@ -0,0 +1,24 @@
+package models
+
+import "gorm.io/gorm"
+
+// Olt is one device in the local OLT inventory. The scheduler and the
+// excessCommands helpers only ever connect to enabled rows from this table.
+type Olt struct {
+	gorm.Model
+	Name        string `gorm:"index;not null" json:"name"`
+	Host        string `gorm:"uniqueIndex;not null" json:"host"`
+	Site        string `gorm:"index" json:"site"`
+	Vendor      string `gorm:"index;size:20" json:"vendor"`
+	DeviceModel string `gorm:"column:model;size:50" json:"model"`
+	// Firmware is the software release, e.g. "6.2.04", used to pick the
+	// TextFSM templates for the device's output.
+	Firmware string    `gorm:"size:50" json:"firmware"`
+	Tags     JSONSlice `gorm:"type:jsonb" json:"tags"`
+	// Transport is "ssh" or "telnet" for legacy shelves without SSH.
+	Transport string `gorm:"size:10;default:ssh" json:"transport"`
+	// HealthSource is "ssh" to scrape health from the CLI or "snmp" to poll
+	// it.
+	HealthSource string `gorm:"size:10;default:ssh" json:"health_source"`
+	Enabled      bool   `gorm:"default:true" json:"enabled"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OltEvent is one alarm an OLT reported on its own, through an SNMP trap or
+// a syslog message. Kind is "los", "dying_gasp", "board_down" or
+// "protection_switch"; Object is the ONT, port or slot it concerns when the
+// message names one.
+type OltEvent struct {
+	gorm.Model
+	Host       string    `gorm:"index;not null" json:"host"`
+	Device     string    `gorm:"index" json:"device"`
+	Site       string    `gorm:"index" json:"site"`
+	Source     string    `gorm:"size:10" json:"source"`
+	Kind       string    `gorm:"index;size:30" json:"kind"`
+	Severity   string    `gorm:"size:20" json:"severity"`
+	Object     string    `json:"object"`
+	Cleared    bool      `json:"cleared"`
+	Message    string    `json:"message"`
+	OccurredAt time.Time `gorm:"index" json:"occurred_at"`
+}
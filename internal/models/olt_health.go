@@ -17,7 +17,11 @@ type OltHealth struct {
 	Uptime       string    `json:"uptime"`
 	CpuLoads     JSONSlice `gorm:"type:jsonb" json:"cpu_loads"`
 	Temperatures JSONSlice `gorm:"type:jsonb" json:"temperatures"`
-	MeasuredAt   time.Time `gorm:"autoUpdateTime" json:"measured_at"`
+	// Boards is only filled by SNMP polls.
+	Boards JSONSlice `gorm:"type:jsonb" json:"boards"`
+	// Source is "ssh" or "snmp".
+	Source     string    `gorm:"size:10;default:ssh" json:"source"`
+	MeasuredAt time.Time `gorm:"autoUpdateTime" json:"measured_at"`
 }
 
 // JSONSlice stores arbitrary JSON arrays in a JSONB column.
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// OntSerial places an ONT serial number on an OLT. It is fed by the status
+// scans and by Nokia backups; a serial found at several places has a row for
+// each, and LastSeen tells which is current. Vendor is the serial's vendor
+// ID, e.g. "HWTC" or "ALCL", and Source is "status" or "config", whichever
+// saw the ONT last.
+type OntSerial struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	Sernum     string    `gorm:"uniqueIndex:idx_ont_serial_location;size:32;not null" json:"sernum"`
+	Vendor     string    `gorm:"index;size:8" json:"vendor"`
+	Device     string    `gorm:"index;not null" json:"device"`
+	Site       string    `gorm:"index" json:"site"`
+	Host       string    `gorm:"uniqueIndex:idx_ont_serial_location;not null" json:"host"`
+	OntIdx     string    `gorm:"uniqueIndex:idx_ont_serial_location;not null" json:"ont_idx"`
+	Desc1      string    `json:"desc1"`
+	Desc2      string    `json:"desc2"`
+	AdminState string    `gorm:"size:16" json:"admin_state"`
+	OperState  string    `gorm:"size:16" json:"oper_state"`
+	Source     string    `gorm:"size:10" json:"source"`
+	LastSeen   time.Time `gorm:"index" json:"last_seen"`
+}
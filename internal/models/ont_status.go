@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OntStatus is one ONT's state from the last status scan, one row per host
+// and index so IDs stay stable across scans. ExpectedSernum is the serial
+// first seen at this index, kept across scans until an admin accepts a new
+// one, so a swapped or cloned ONT shows as a mismatch.
+type OntStatus struct {
+	gorm.Model
+	Device         string    `gorm:"index;not null" json:"device"`
+	Site           string    `gorm:"index;not null" json:"site"`
+	Host           string    `gorm:"uniqueIndex:idx_ont_status_location;not null" json:"host"`
+	Pon            string    `json:"pon"`
+	OntIdx         string    `gorm:"uniqueIndex:idx_ont_status_location;not null" json:"ont_idx"`
+	Sernum         string    `gorm:"index;size:32" json:"sernum"`
+	ExpectedSernum string    `gorm:"size:32" json:"expected_sernum"`
+	AdminStatus    string    `gorm:"size:16" json:"admin_status"`
+	OperStatus     string    `gorm:"index;size:16" json:"oper_status"`
+	OltRx          *float64  `json:"olt_rx"`
+	DistanceKm     *float64  `json:"distance_km"`
+	MeasuredAt     time.Time `gorm:"autoUpdateTime" json:"measured_at"`
+}
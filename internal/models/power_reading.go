@@ -6,12 +6,23 @@ import (
 	"gorm.io/gorm"
 )
 
+// PowerReading is one ONT's optics from the last power scan. OltRx is the
+// upstream level at the OLT; the ONT readings are null when the OLT does not
+// report them. LegacyOltRx marks Nokia rows stored before the optics table
+// was read by header: their OltRx is the first column, the ONT's downstream
+// level, which is also copied to OntRx.
 type PowerReading struct {
 	gorm.Model
-	Device     string    `gorm:"index;not null" json:"device"`
-	Site       string    `gorm:"index;not null" json:"site"`
-	Host       string    `gorm:"index;not null" json:"host"`
-	OntIdx     string    `gorm:"not null" json:"ont_idx"`
-	OltRx      float64   `json:"olt_rx"`
-	MeasuredAt time.Time `gorm:"autoCreateTime" json:"measured_at"`
+	Device      string    `gorm:"index;not null" json:"device"`
+	Site        string    `gorm:"index;not null" json:"site"`
+	Host        string    `gorm:"index;not null" json:"host"`
+	OntIdx      string    `gorm:"not null" json:"ont_idx"`
+	OltRx       float64   `json:"olt_rx"`
+	OntRx       *float64  `json:"ont_rx"`
+	OntTx       *float64  `json:"ont_tx"`
+	LaserBias   *float64  `json:"laser_bias"`
+	Temperature *float64  `json:"temperature"`
+	Voltage     *float64  `json:"voltage"`
+	LegacyOltRx bool      `gorm:"not null;default:false" json:"legacy_olt_rx,omitempty"`
+	MeasuredAt  time.Time `gorm:"autoCreateTime" json:"measured_at"`
 }
@@ -10,6 +10,8 @@ type BackupRepository interface {
 	GetAll() ([]models.OltBackups, error)
 	GetBySite(site string) ([]models.OltBackups, error)
 	GetByID(id uint) (*models.OltBackups, error)
+	Latest(host string) (*models.OltBackups, error)
+	LatestPerHost() ([]models.OltBackups, error)
 }
 
 type backupRepository struct {
@@ -44,3 +46,19 @@ func (r *backupRepository) GetByID(id uint) (*models.OltBackups, error) {
 	}
 	return &b, nil
 }
+
+func (r *backupRepository) Latest(host string) (*models.OltBackups, error) {
+	var b models.OltBackups
+	err := r.DB.Where("host = ?", host).Order("created_at DESC").First(&b).Error
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// LatestPerHost returns the newest backup of every host.
+func (r *backupRepository) LatestPerHost() ([]models.OltBackups, error) {
+	var out []models.OltBackups
+	err := r.DB.Select("DISTINCT ON (host) *").Order("host, created_at DESC").Find(&out).Error
+	return out, err
+}
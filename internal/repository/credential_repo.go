@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"gorm.io/gorm"
+)
+
+type CredentialRepository interface {
+	Create(c *models.CredentialSet) error
+	Update(c *models.CredentialSet) error
+	Delete(id uint) error
+	GetByID(id uint) (*models.CredentialSet, error)
+	GetAll() ([]models.CredentialSet, error)
+	// GetForOLT returns the sets that may apply to an OLT: its own, its
+	// site's and the default.
+	GetForOLT(host, site string) ([]models.CredentialSet, error)
+}
+
+type credentialRepository struct {
+	DB *gorm.DB
+}
+
+func NewCredentialRepository(db *gorm.DB) CredentialRepository {
+	return &credentialRepository{DB: db}
+}
+
+func (r *credentialRepository) Create(c *models.CredentialSet) error {
+	return r.DB.Create(c).Error
+}
+
+func (r *credentialRepository) Update(c *models.CredentialSet) error {
+	return r.DB.Save(c).Error
+}
+
+func (r *credentialRepository) Delete(id uint) error {
+	// hard delete so the scope/target pair can be reused
+	return r.DB.Unscoped().Delete(&models.CredentialSet{}, id).Error
+}
+
+func (r *credentialRepository) GetByID(id uint) (*models.CredentialSet, error) {
+	var c models.CredentialSet
+	err := r.DB.First(&c, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("credential set not found")
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *credentialRepository) GetAll() ([]models.CredentialSet, error) {
+	var data []models.CredentialSet
+	err := r.DB.Order("scope, target, name").Find(&data).Error
+	return data, err
+}
+
+func (r *credentialRepository) GetForOLT(host, site string) ([]models.CredentialSet, error) {
+	var data []models.CredentialSet
+	err := r.DB.
+		Where("(scope = ? AND target = ?) OR (scope = ? AND target = ?) OR scope = ?",
+			models.CredentialScopeOLT, host,
+			models.CredentialScopeSite, site,
+			models.CredentialScopeDefault).
+		Find(&data).Error
+	return data, err
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"gorm.io/gorm"
+)
+
+// EventFilter narrows OltEventRepository.List. Zero fields match everything.
+type EventFilter struct {
+	Host  string
+	Kind  string
+	Since time.Time
+	Limit int
+}
+
+type OltEventRepository interface {
+	Create(e *models.OltEvent) error
+	List(f EventFilter) ([]models.OltEvent, error)
+}
+
+type oltEventRepository struct {
+	DB *gorm.DB
+}
+
+func NewOltEventRepository(db *gorm.DB) OltEventRepository {
+	return &oltEventRepository{DB: db}
+}
+
+func (r *oltEventRepository) Create(e *models.OltEvent) error {
+	return r.DB.Create(e).Error
+}
+
+// List returns the newest events first.
+func (r *oltEventRepository) List(f EventFilter) ([]models.OltEvent, error) {
+	var out []models.OltEvent
+	q := r.DB.Order("occurred_at DESC")
+	if f.Host != "" {
+		q = q.Where("host = ?", f.Host)
+	}
+	if f.Kind != "" {
+		q = q.Where("kind = ?", f.Kind)
+	}
+	if !f.Since.IsZero() {
+		q = q.Where("occurred_at >= ?", f.Since)
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	err := q.Find(&out).Error
+	return out, err
+}
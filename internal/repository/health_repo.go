@@ -23,7 +23,7 @@ func NewHealthRepository(db *gorm.DB) HealthRepository {
 func (r *healthRepository) Upsert(h *models.OltHealth) error {
 	return r.DB.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "host"}},
-		DoUpdates: clause.AssignmentColumns([]string{"device", "site", "uptime", "cpu_loads", "temperatures", "measured_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"device", "site", "uptime", "cpu_loads", "temperatures", "boards", "source", "measured_at"}),
 	}).Create(h).Error
 }
 
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrHostKeyNotFound is returned for hosts that have no pinned key yet.
+var ErrHostKeyNotFound = errors.New("host key not found")
+
+type HostKeyRepository interface {
+	GetByHost(host string) (*models.HostKey, error)
+	GetAll() ([]models.HostKey, error)
+	Save(k *models.HostKey) error
+	Delete(host string) error
+}
+
+type hostKeyRepository struct {
+	DB *gorm.DB
+}
+
+func NewHostKeyRepository(db *gorm.DB) HostKeyRepository {
+	return &hostKeyRepository{DB: db}
+}
+
+func (r *hostKeyRepository) GetByHost(host string) (*models.HostKey, error) {
+	var k models.HostKey
+	err := r.DB.Where("host = ?", host).First(&k).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrHostKeyNotFound
+		}
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *hostKeyRepository) GetAll() ([]models.HostKey, error) {
+	var data []models.HostKey
+	err := r.DB.Order("status, host").Find(&data).Error
+	return data, err
+}
+
+func (r *hostKeyRepository) Save(k *models.HostKey) error {
+	return r.DB.Save(k).Error
+}
+
+func (r *hostKeyRepository) Delete(host string) error {
+	// hard delete so the next connect pins a fresh key
+	return r.DB.Unscoped().Where("host = ?", host).Delete(&models.HostKey{}).Error
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"gorm.io/gorm"
+)
+
+type JobReportRepository interface {
+	Create(r *models.JobReport) error
+	List(job string, limit int) ([]models.JobReport, error)
+	GetByID(id uint) (*models.JobReport, error)
+}
+
+type jobReportRepository struct {
+	DB *gorm.DB
+}
+
+func NewJobReportRepository(db *gorm.DB) JobReportRepository {
+	return &jobReportRepository{DB: db}
+}
+
+func (r *jobReportRepository) Create(rep *models.JobReport) error {
+	return r.DB.Create(rep).Error
+}
+
+// List returns the newest reports first, optionally for one job only.
+func (r *jobReportRepository) List(job string, limit int) ([]models.JobReport, error) {
+	var out []models.JobReport
+	q := r.DB.Order("started_at DESC").Limit(limit)
+	if job != "" {
+		q = q.Where("job = ?", job)
+	}
+	err := q.Find(&out).Error
+	return out, err
+}
+
+func (r *jobReportRepository) GetByID(id uint) (*models.JobReport, error) {
+	var rep models.JobReport
+	err := r.DB.First(&rep, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
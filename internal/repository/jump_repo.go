@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"gorm.io/gorm"
+)
+
+type JumpRepository interface {
+	CreateHost(h *models.JumpHost) error
+	UpdateHost(h *models.JumpHost) error
+	DeleteHost(id uint) error
+	GetHost(id uint) (*models.JumpHost, error)
+	GetHostByName(name string) (*models.JumpHost, error)
+	ListHosts() ([]models.JumpHost, error)
+
+	CreateRoute(r *models.JumpRoute) error
+	UpdateRoute(r *models.JumpRoute) error
+	DeleteRoute(id uint) error
+	GetRoute(id uint) (*models.JumpRoute, error)
+	ListRoutes() ([]models.JumpRoute, error)
+	// GetRoutesForOLT returns the OLT's own route and its site's route.
+	GetRoutesForOLT(host, site string) ([]models.JumpRoute, error)
+}
+
+type jumpRepository struct {
+	DB *gorm.DB
+}
+
+func NewJumpRepository(db *gorm.DB) JumpRepository {
+	return &jumpRepository{DB: db}
+}
+
+func (r *jumpRepository) CreateHost(h *models.JumpHost) error {
+	return r.DB.Create(h).Error
+}
+
+func (r *jumpRepository) UpdateHost(h *models.JumpHost) error {
+	return r.DB.Save(h).Error
+}
+
+func (r *jumpRepository) DeleteHost(id uint) error {
+	return r.DB.Unscoped().Delete(&models.JumpHost{}, id).Error
+}
+
+func (r *jumpRepository) GetHost(id uint) (*models.JumpHost, error) {
+	var h models.JumpHost
+	err := r.DB.First(&h, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("jump host not found")
+		}
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *jumpRepository) GetHostByName(name string) (*models.JumpHost, error) {
+	var h models.JumpHost
+	err := r.DB.Where("name = ?", name).First(&h).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("jump host not found")
+		}
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *jumpRepository) ListHosts() ([]models.JumpHost, error) {
+	var data []models.JumpHost
+	err := r.DB.Order("name").Find(&data).Error
+	return data, err
+}
+
+func (r *jumpRepository) CreateRoute(rt *models.JumpRoute) error {
+	return r.DB.Create(rt).Error
+}
+
+func (r *jumpRepository) UpdateRoute(rt *models.JumpRoute) error {
+	return r.DB.Save(rt).Error
+}
+
+func (r *jumpRepository) DeleteRoute(id uint) error {
+	return r.DB.Unscoped().Delete(&models.JumpRoute{}, id).Error
+}
+
+func (r *jumpRepository) GetRoute(id uint) (*models.JumpRoute, error) {
+	var rt models.JumpRoute
+	err := r.DB.First(&rt, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("jump route not found")
+		}
+		return nil, err
+	}
+	return &rt, nil
+}
+
+func (r *jumpRepository) ListRoutes() ([]models.JumpRoute, error) {
+	var data []models.JumpRoute
+	err := r.DB.Order("scope, target").Find(&data).Error
+	return data, err
+}
+
+func (r *jumpRepository) GetRoutesForOLT(host, site string) ([]models.JumpRoute, error) {
+	var data []models.JumpRoute
+	err := r.DB.
+		Where("(scope = ? AND target = ?) OR (scope = ? AND target = ?)",
+			models.CredentialScopeOLT, host,
+			models.CredentialScopeSite, site).
+		Find(&data).Error
+	return data, err
+}
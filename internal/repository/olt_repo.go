@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"gorm.io/gorm"
+)
+
+type OltRepository interface {
+	Create(olt *models.Olt) error
+	Update(olt *models.Olt) error
+	Delete(id uint) error
+	GetByID(id uint) (*models.Olt, error)
+	GetByHost(host string) (*models.Olt, error)
+	GetAll() ([]models.Olt, error)
+	GetEnabled() ([]models.Olt, error)
+	Upsert(olts []models.Olt, restore bool) (created, updated, skipped int, err error)
+}
+
+type oltRepository struct {
+	DB *gorm.DB
+}
+
+func NewOltRepository(db *gorm.DB) OltRepository {
+	return &oltRepository{DB: db}
+}
+
+func (r *oltRepository) Create(olt *models.Olt) error {
+	enabled := olt.Enabled
+	if err := r.DB.Create(olt).Error; err != nil {
+		return err
+	}
+	// gorm skips zero values for columns with a default, so a disabled OLT
+	// would otherwise come back enabled.
+	if !enabled {
+		olt.Enabled = false
+		return r.DB.Model(olt).Update("enabled", false).Error
+	}
+	return nil
+}
+
+func (r *oltRepository) Update(olt *models.Olt) error {
+	return r.DB.Save(olt).Error
+}
+
+func (r *oltRepository) Delete(id uint) error {
+	return r.DB.Delete(&models.Olt{}, id).Error
+}
+
+func (r *oltRepository) GetByID(id uint) (*models.Olt, error) {
+	var o models.Olt
+	err := r.DB.First(&o, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("olt not found")
+		}
+		return nil, err
+	}
+	return &o, nil
+}
+
+// GetByHost returns the OLT at host, including a soft-deleted one: the host
+// stays taken until the row is restored or purged.
+func (r *oltRepository) GetByHost(host string) (*models.Olt, error) {
+	var o models.Olt
+	err := r.DB.Unscoped().Where("host = ?", host).First(&o).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("olt not found")
+		}
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *oltRepository) GetAll() ([]models.Olt, error) {
+	var out []models.Olt
+	err := r.DB.Order("site, name").Find(&out).Error
+	return out, err
+}
+
+func (r *oltRepository) GetEnabled() ([]models.Olt, error) {
+	var out []models.Olt
+	err := r.DB.Where("enabled = ?", true).Order("site, name").Find(&out).Error
+	return out, err
+}
+
+// Upsert merges olts into the inventory keyed by host. Existing rows have
+// their non-empty fields overwritten. A soft-deleted row is skipped, so a
+// sync never brings back a device an admin deleted, unless restore is set.
+// New rows take their enabled flag from the input; the flag of an existing
+// row is left untouched so a sync never re-enables a device an admin
+// switched off. A new row without a vendor is taken as Nokia, like Create
+// does.
+func (r *oltRepository) Upsert(olts []models.Olt, restore bool) (created, updated, skipped int, err error) {
+	err = r.DB.Transaction(func(tx *gorm.DB) error {
+		for _, in := range olts {
+			var cur models.Olt
+			res := tx.Unscoped().Where("host = ?", in.Host).Limit(1).Find(&cur)
+			if res.Error != nil {
+				return res.Error
+			}
+
+			if res.RowsAffected == 0 {
+				if in.Vendor == "" {
+					in.Vendor = "nokia"
+				}
+				enabled := in.Enabled
+				if err := tx.Create(&in).Error; err != nil {
+					return err
+				}
+				if !enabled {
+					if err := tx.Model(&in).Update("enabled", false).Error; err != nil {
+						return err
+					}
+				}
+				created++
+				continue
+			}
+			if cur.DeletedAt.Valid && !restore {
+				skipped++
+				continue
+			}
+
+			if in.Name != "" {
+				cur.Name = in.Name
+			}
+			if in.Site != "" {
+				cur.Site = in.Site
+			}
+			if in.Vendor != "" {
+				cur.Vendor = in.Vendor
+			}
+			if in.DeviceModel != "" {
+				cur.DeviceModel = in.DeviceModel
+			}
+			if in.Firmware != "" {
+				cur.Firmware = in.Firmware
+			}
+			if len(in.Tags) > 0 {
+				cur.Tags = in.Tags
+			}
+			if in.Transport != "" {
+				cur.Transport = in.Transport
+			}
+			if in.HealthSource != "" {
+				cur.HealthSource = in.HealthSource
+			}
+			cur.DeletedAt = gorm.DeletedAt{}
+			if err := tx.Unscoped().Save(&cur).Error; err != nil {
+				return err
+			}
+			updated++
+		}
+		return nil
+	})
+	return created, updated, skipped, err
+}
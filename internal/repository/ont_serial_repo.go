@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SerialFilter narrows OntSerialRepository.Search. Prefix matches the start
+// of the serial; Vendors keeps the given vendor IDs.
+type SerialFilter struct {
+	Prefix  string
+	Vendors []string
+	Limit   int
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type OntSerialRepository interface {
+	Upsert(rows []models.OntSerial) error
+	Search(f SerialFilter) ([]models.OntSerial, error)
+}
+
+type ontSerialRepository struct {
+	DB *gorm.DB
+}
+
+func NewOntSerialRepository(db *gorm.DB) OntSerialRepository {
+	return &ontSerialRepository{DB: db}
+}
+
+// Upsert records where each serial was seen. A feed that leaves a field
+// empty, like a backup's oper state, keeps the value the other one stored.
+func (r *ontSerialRepository) Upsert(rows []models.OntSerial) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	updates := clause.AssignmentColumns([]string{"vendor", "device", "site", "source", "last_seen"})
+	for _, col := range []string{"desc1", "desc2", "admin_state", "oper_state"} {
+		updates = append(updates, clause.Assignment{
+			Column: clause.Column{Name: col},
+			Value:  gorm.Expr("COALESCE(NULLIF(excluded." + col + ", ''), ont_serials." + col + ")"),
+		})
+	}
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "sernum"}, {Name: "host"}, {Name: "ont_idx"}},
+		DoUpdates: updates,
+	}).CreateInBatches(rows, 100).Error
+}
+
+// Search returns the matching serials, the most recently seen first.
+func (r *ontSerialRepository) Search(f SerialFilter) ([]models.OntSerial, error) {
+	var out []models.OntSerial
+	q := r.DB.Order("last_seen DESC, sernum")
+	if f.Prefix != "" {
+		q = q.Where("sernum LIKE ?", likeEscaper.Replace(f.Prefix)+"%")
+	}
+	if len(f.Vendors) > 0 {
+		q = q.Where("vendor IN ?", f.Vendors)
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	err := q.Find(&out).Error
+	return out, err
+}
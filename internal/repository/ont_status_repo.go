@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OntStatusWithDesc is an ONT's status with its descriptions.
+type OntStatusWithDesc struct {
+	models.OntStatus
+	Desc1 string `json:"desc1"`
+	Desc2 string `json:"desc2"`
+}
+
+// OntStatusFilter narrows OntStatusRepository.List. Zero fields match
+// everything.
+type OntStatusFilter struct {
+	Device      string
+	Host        string
+	AdminStatus string
+	OperStatus  string
+	// DistanceAbove keeps ONTs farther than this many km.
+	DistanceAbove *float64
+	// SerialMismatch keeps ONTs whose serial is not the expected one.
+	SerialMismatch bool
+	// Search matches the ONT index, serial and both descriptions.
+	Search string
+	Limit  int
+}
+
+type OntStatusRepository interface {
+	Replace(device, site, host string, rows []models.OntStatus) error
+	List(f OntStatusFilter) ([]OntStatusWithDesc, error)
+	GetByID(id uint) (*models.OntStatus, error)
+	AcceptSerial(id uint) error
+}
+
+type ontStatusRepository struct {
+	DB *gorm.DB
+}
+
+func NewOntStatusRepository(db *gorm.DB) OntStatusRepository {
+	return &ontStatusRepository{DB: db}
+}
+
+// Replace makes rows the host's ONT states: known ONTs are updated in place,
+// new ones added and the ones no longer on the OLT deleted. The expected
+// serial of an ONT is kept, or taken from rows on first sight.
+func (r *ontStatusRepository) Replace(device, site, host string, rows []models.OntStatus) error {
+	byIdx := make(map[string]int, len(rows))
+	var unique []models.OntStatus
+	for _, row := range rows {
+		row.Device = device
+		row.Site = site
+		row.Host = host
+		row.ExpectedSernum = row.Sernum
+		if i, ok := byIdx[row.OntIdx]; ok {
+			unique[i] = row
+			continue
+		}
+		byIdx[row.OntIdx] = len(unique)
+		unique = append(unique, row)
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		gone := tx.Unscoped().Where("host = ?", host)
+		if len(unique) > 0 {
+			idx := make([]string, len(unique))
+			for i, row := range unique {
+				idx[i] = row.OntIdx
+			}
+			gone = gone.Where("ont_idx NOT IN ?", idx)
+		}
+		if err := gone.Delete(&models.OntStatus{}).Error; err != nil {
+			return err
+		}
+		if len(unique) == 0 {
+			return nil
+		}
+
+		updates := clause.AssignmentColumns([]string{"device", "site", "pon", "sernum", "admin_status", "oper_status", "olt_rx", "distance_km", "measured_at", "updated_at"})
+		updates = append(updates, clause.Assignment{
+			Column: clause.Column{Name: "expected_sernum"},
+			Value:  gorm.Expr("COALESCE(NULLIF(ont_statuses.expected_sernum, ''), excluded.expected_sernum)"),
+		})
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "host"}, {Name: "ont_idx"}},
+			DoUpdates: updates,
+		}).CreateInBatches(unique, 100).Error
+	})
+}
+
+func (r *ontStatusRepository) List(f OntStatusFilter) ([]OntStatusWithDesc, error) {
+	descJoin := "LEFT JOIN ont_descriptions ON ont_statuses.ont_idx = ont_descriptions.ont_idx AND ont_statuses.host = ont_descriptions.host AND ont_descriptions.deleted_at IS NULL"
+
+	q := r.DB.Model(&models.OntStatus{}).
+		Select("ont_statuses.*, COALESCE(ont_descriptions.desc1, '') as desc1, COALESCE(ont_descriptions.desc2, '') as desc2").
+		Joins(descJoin).
+		Order("ont_statuses.host, ont_statuses.ont_idx")
+	if f.Device != "" {
+		q = q.Where("ont_statuses.device = ?", f.Device)
+	}
+	if f.Host != "" {
+		q = q.Where("ont_statuses.host = ?", f.Host)
+	}
+	if f.AdminStatus != "" {
+		q = q.Where("ont_statuses.admin_status = ?", f.AdminStatus)
+	}
+	if f.OperStatus != "" {
+		q = q.Where("ont_statuses.oper_status = ?", f.OperStatus)
+	}
+	if f.DistanceAbove != nil {
+		q = q.Where("ont_statuses.distance_km > ?", *f.DistanceAbove)
+	}
+	if f.SerialMismatch {
+		q = q.Where("ont_statuses.sernum <> ont_statuses.expected_sernum")
+	}
+	if f.Search != "" {
+		pattern := "%" + f.Search + "%"
+		q = q.Where("ont_statuses.ont_idx ILIKE ? OR ont_statuses.sernum ILIKE ? OR ont_descriptions.desc1 ILIKE ? OR ont_descriptions.desc2 ILIKE ?", pattern, pattern, pattern, pattern)
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+
+	var out []OntStatusWithDesc
+	err := q.Scan(&out).Error
+	return out, err
+}
+
+func (r *ontStatusRepository) GetByID(id uint) (*models.OntStatus, error) {
+	var s models.OntStatus
+	if err := r.DB.First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// AcceptSerial makes the ONT's current serial the expected one.
+func (r *ontStatusRepository) AcceptSerial(id uint) error {
+	return r.DB.Model(&models.OntStatus{}).Where("id = ?", id).
+		Update("expected_sernum", gorm.Expr("sernum")).Error
+}
@@ -21,15 +21,32 @@ type DevicePowerSummary struct {
 }
 
 type PowerReadingWithDesc struct {
-	ID         uint      `json:"ID"`
-	Device     string    `json:"device"`
-	Site       string    `json:"site"`
-	Host       string    `json:"host"`
-	OntIdx     string    `json:"ont_idx"`
-	OltRx      float64   `json:"olt_rx"`
-	MeasuredAt time.Time `json:"measured_at"`
-	Desc1      string    `json:"desc1"`
-	Desc2      string    `json:"desc2"`
+	ID          uint      `json:"ID"`
+	Device      string    `json:"device"`
+	Site        string    `json:"site"`
+	Host        string    `json:"host"`
+	OntIdx      string    `json:"ont_idx"`
+	OltRx       float64   `json:"olt_rx"`
+	OntRx       *float64  `json:"ont_rx"`
+	OntTx       *float64  `json:"ont_tx"`
+	LaserBias   *float64  `json:"laser_bias"`
+	Temperature *float64  `json:"temperature"`
+	Voltage     *float64  `json:"voltage"`
+	LegacyOltRx bool      `json:"legacy_olt_rx,omitempty"`
+	MeasuredAt  time.Time `json:"measured_at"`
+	Desc1       string    `json:"desc1"`
+	Desc2       string    `json:"desc2"`
+}
+
+// PowerFilter narrows GetPaginated. Search matches the ONT index and both
+// descriptions; the thresholds are skipped when nil, and a reading the OLT
+// did not report never matches one.
+type PowerFilter struct {
+	Device           string
+	Search           string
+	OltRxBelow       *float64
+	OntRxBelow       *float64
+	TemperatureAbove *float64
 }
 
 type PaginatedReadings struct {
@@ -44,7 +61,7 @@ type PowerRepository interface {
 	BulkInsert(device, site, host string, readings []models.PowerReading) error
 	DeleteByHost(host string) error
 	GetAll() ([]models.PowerReading, error)
-	GetPaginated(page, perPage int, device, search string) (*PaginatedReadings, error)
+	GetPaginated(page, perPage int, f PowerFilter) (*PaginatedReadings, error)
 	GetByHost(host string) ([]models.PowerReading, error)
 	GetWeak(threshold float64) ([]models.PowerReading, error)
 	GetDevices() ([]DeviceInfo, error)
@@ -79,18 +96,33 @@ func (r *powerRepository) GetAll() ([]models.PowerReading, error) {
 	return out, err
 }
 
-func (r *powerRepository) GetPaginated(page, perPage int, device, search string) (*PaginatedReadings, error) {
+// where applies f to a query over power_readings joined with
+// ont_descriptions.
+func (f PowerFilter) where(q *gorm.DB) *gorm.DB {
+	if f.Device != "" {
+		q = q.Where("power_readings.device = ?", f.Device)
+	}
+	if f.Search != "" {
+		pattern := "%" + f.Search + "%"
+		q = q.Where("power_readings.ont_idx ILIKE ? OR ont_descriptions.desc1 ILIKE ? OR ont_descriptions.desc2 ILIKE ?", pattern, pattern, pattern)
+	}
+	if f.OltRxBelow != nil {
+		q = q.Where("power_readings.olt_rx < ? AND NOT power_readings.legacy_olt_rx", *f.OltRxBelow)
+	}
+	if f.OntRxBelow != nil {
+		q = q.Where("power_readings.ont_rx < ?", *f.OntRxBelow)
+	}
+	if f.TemperatureAbove != nil {
+		q = q.Where("power_readings.temperature > ?", *f.TemperatureAbove)
+	}
+	return q
+}
+
+func (r *powerRepository) GetPaginated(page, perPage int, f PowerFilter) (*PaginatedReadings, error) {
 	descJoin := "LEFT JOIN ont_descriptions ON power_readings.ont_idx = ont_descriptions.ont_idx AND power_readings.host = ont_descriptions.host AND ont_descriptions.deleted_at IS NULL"
 
 	// Count query — Model() handles soft delete automatically
-	countQ := r.DB.Model(&models.PowerReading{}).Joins(descJoin)
-	if device != "" {
-		countQ = countQ.Where("power_readings.device = ?", device)
-	}
-	if search != "" {
-		pattern := "%" + search + "%"
-		countQ = countQ.Where("power_readings.ont_idx ILIKE ? OR ont_descriptions.desc1 ILIKE ? OR ont_descriptions.desc2 ILIKE ?", pattern, pattern, pattern)
-	}
+	countQ := f.where(r.DB.Model(&models.PowerReading{}).Joins(descJoin))
 
 	var total int64
 	if err := countQ.Count(&total).Error; err != nil {
@@ -104,15 +136,9 @@ func (r *powerRepository) GetPaginated(page, perPage int, device, search string)
 
 	// Data query — separate fresh query to avoid shared state with Count
 	dataQ := r.DB.Model(&models.PowerReading{}).
-		Select("power_readings.id, power_readings.device, power_readings.site, power_readings.host, power_readings.ont_idx, power_readings.olt_rx, power_readings.measured_at, COALESCE(ont_descriptions.desc1, '') as desc1, COALESCE(ont_descriptions.desc2, '') as desc2").
+		Select("power_readings.id, power_readings.device, power_readings.site, power_readings.host, power_readings.ont_idx, power_readings.olt_rx, power_readings.ont_rx, power_readings.ont_tx, power_readings.laser_bias, power_readings.temperature, power_readings.voltage, power_readings.legacy_olt_rx, power_readings.measured_at, COALESCE(ont_descriptions.desc1, '') as desc1, COALESCE(ont_descriptions.desc2, '') as desc2").
 		Joins(descJoin)
-	if device != "" {
-		dataQ = dataQ.Where("power_readings.device = ?", device)
-	}
-	if search != "" {
-		pattern := "%" + search + "%"
-		dataQ = dataQ.Where("power_readings.ont_idx ILIKE ? OR ont_descriptions.desc1 ILIKE ? OR ont_descriptions.desc2 ILIKE ?", pattern, pattern, pattern)
-	}
+	dataQ = f.where(dataQ)
 
 	var data []PowerReadingWithDesc
 	err := dataQ.Order("power_readings.olt_rx ASC").Offset((page - 1) * perPage).Limit(perPage).Scan(&data).Error
@@ -135,9 +161,11 @@ func (r *powerRepository) GetByHost(host string) ([]models.PowerReading, error)
 	return out, err
 }
 
+// GetWeak lists the readings whose OLT Rx level is below threshold. Legacy
+// rows are left out: their olt_rx is the ONT's level, which reads higher.
 func (r *powerRepository) GetWeak(threshold float64) ([]models.PowerReading, error) {
 	var out []models.PowerReading
-	err := r.DB.Where("olt_rx < ?", threshold).Order("olt_rx").Find(&out).Error
+	err := r.DB.Where("olt_rx < ? AND NOT legacy_olt_rx", threshold).Order("olt_rx").Find(&out).Error
 	return out, err
 }
 
@@ -153,7 +181,7 @@ func (r *powerRepository) GetDevices() ([]DeviceInfo, error) {
 func (r *powerRepository) GetSummary(threshold float64) ([]DevicePowerSummary, error) {
 	var out []DevicePowerSummary
 	err := r.DB.Model(&models.PowerReading{}).
-		Select("device, site, COUNT(*) as total, SUM(CASE WHEN olt_rx < ? THEN 1 ELSE 0 END) as weak_count", threshold).
+		Select("device, site, COUNT(*) as total, SUM(CASE WHEN olt_rx < ? AND NOT legacy_olt_rx THEN 1 ELSE 0 END) as weak_count", threshold).
 		Group("device, site").
 		Order("site, device").
 		Find(&out).Error
@@ -18,6 +18,15 @@ func Setup(
 	portH *handlers.PortHandler,
 	backupH *handlers.BackupHandler,
 	userH *handlers.UserhHandler,
+	oltH *handlers.OltHandler,
+	credH *handlers.CredentialHandler,
+	hostKeyH *handlers.HostKeyHandler,
+	jumpH *handlers.JumpHandler,
+	reportH *handlers.JobReportHandler,
+	eventH *handlers.EventHandler,
+	ontH *handlers.OntStatusHandler,
+	configH *handlers.ConfigHandler,
+	serialH *handlers.OntSerialHandler,
 	authH *handlers.AuthHandler,
 	pageH *handlers.PageHandler,
 ) {
@@ -57,6 +66,8 @@ func Setup(
 		api.POST("/auth/refresh", authH.Refresh)
 
 		api.GET("/devices", powerH.GetDevices)
+		api.GET("/olts/reachability", oltH.Reachability)
+		api.GET("/olts/sessions", oltH.Sessions)
 
 		power := api.Group("/power")
 		{
@@ -83,10 +94,31 @@ func Setup(
 			ports.GET("/:host", portH.GetByHost)
 		}
 
+		jobs := api.Group("/jobs")
+		{
+			jobs.GET("/reports", reportH.List)
+			jobs.GET("/reports/:id", reportH.Get)
+		}
+
+		api.GET("/events", eventH.List)
+
+		onts := api.Group("/onts")
+		{
+			onts.GET("/status", ontH.List)
+			onts.GET("/search", serialH.Search)
+		}
+
 		backups := api.Group("/backups")
 		{
 			backups.GET("", backupH.GetAll)
 			backups.GET("/:id/download", backupH.Download)
+			backups.GET("/:id/config", configH.GetBackup)
+		}
+
+		configs := api.Group("/config")
+		{
+			configs.GET("/onts", configH.Onts)
+			configs.GET("/:host", configH.Get)
 		}
 
 		// Admin-only API routes
@@ -98,5 +130,53 @@ func Setup(
 			users.PUT("/:id", userH.UpdateUser)
 			users.DELETE("/:id", userH.DeleteUser)
 		}
+
+		olts := api.Group("/admin/olts")
+		olts.Use(middleware.RoleGuard("admin"))
+		{
+			olts.GET("", oltH.List)
+			olts.POST("", oltH.Create)
+			olts.POST("/import", oltH.Import)
+			olts.POST("/sync", oltH.Sync)
+			olts.POST("/reachability/:host/reset", oltH.ResetReachability)
+			olts.PUT("/:id", oltH.Update)
+			olts.DELETE("/:id", oltH.Delete)
+		}
+
+		creds := api.Group("/admin/credentials")
+		creds.Use(middleware.RoleGuard("admin"))
+		{
+			creds.GET("", credH.List)
+			creds.POST("", credH.Create)
+			creds.PUT("/:id", credH.Update)
+			creds.DELETE("/:id", credH.Delete)
+		}
+
+		hostKeys := api.Group("/admin/hostkeys")
+		hostKeys.Use(middleware.RoleGuard("admin"))
+		{
+			hostKeys.GET("", hostKeyH.List)
+			hostKeys.POST("/:host/approve", hostKeyH.Approve)
+			hostKeys.DELETE("/:host", hostKeyH.Delete)
+		}
+
+		ontAdmin := api.Group("/admin/onts")
+		ontAdmin.Use(middleware.RoleGuard("admin"))
+		{
+			ontAdmin.POST("/status/:id/accept-serial", ontH.AcceptSerial)
+		}
+
+		jumps := api.Group("/admin/jump")
+		jumps.Use(middleware.RoleGuard("admin"))
+		{
+			jumps.GET("/hosts", jumpH.ListHosts)
+			jumps.POST("/hosts", jumpH.CreateHost)
+			jumps.PUT("/hosts/:id", jumpH.UpdateHost)
+			jumps.DELETE("/hosts/:id", jumpH.DeleteHost)
+			jumps.GET("/routes", jumpH.ListRoutes)
+			jumps.POST("/routes", jumpH.CreateRoute)
+			jumps.PUT("/routes/:id", jumpH.UpdateRoute)
+			jumps.DELETE("/routes/:id", jumpH.DeleteRoute)
+		}
 	}
 }
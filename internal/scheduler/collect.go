@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/shell"
+)
+
+// section is one kind of data gathered by the collection plan, with its own
+// interval and the routine that stores a device's output. only, when set,
+// limits the section to some devices.
+type section struct {
+	name     string
+	event    string
+	interval time.Duration
+	set      shell.CommandSet
+	save     func(r shell.Result, cmds []shell.CommandResult) (int, error)
+	only     func(shell.OLT) bool
+}
+
+func (s *Scheduler) sections() []section {
+	return []section{
+		{"optics", "power_update", s.cfg.PowerScanInterval, shell.Driver.OpticsCommands, s.savePower, nil},
+		{"status", "desc_update", s.cfg.DescScanInterval, shell.Driver.StatusCommands, s.saveStatus, nil},
+		{"ports", "port_update", s.cfg.PortScanInterval, onlyVendor(shell.Driver.PortProtectionCommands, "nokia"), s.savePorts, nil},
+		{"health", "health_update", s.cfg.HealthScanInterval, onlyVendor(shell.Driver.HealthCommands, healthVendors...), s.saveHealth, sshHealth},
+	}
+}
+
+// healthVendors are the vendors whose health output has an extractor.
+var healthVendors = []string{"nokia", "zte"}
+
+// onlyVendor limits set to the given vendors, for sections whose extractor
+// only understands their output.
+func onlyVendor(set shell.CommandSet, vendors ...string) shell.CommandSet {
+	return func(d shell.Driver) []string {
+		for _, v := range vendors {
+			if d.Vendor() == v {
+				return set(d)
+			}
+		}
+		return nil
+	}
+}
+
+// collectInterval is the tick of the collect job: the shortest section
+// interval.
+func (s *Scheduler) collectInterval() time.Duration {
+	tick := time.Duration(0)
+	for _, sec := range s.sections() {
+		if tick == 0 || sec.interval < tick {
+			tick = sec.interval
+		}
+	}
+	return tick
+}
+
+// --- Collection plan job ---
+
+// runCollect logs in to each device once and runs the commands of every
+// section that is due, then hands each section's output to its extractor
+// and repository. Sections fire on the first tick after their interval, with
+// half a tick of slack so a 6h section on a 30m tick does not slip to 6h30.
+// A section no device completed stays due, so it is retried on the next
+// tick instead of waiting out its interval.
+func (s *Scheduler) runCollect() {
+	now := time.Now()
+	slack := s.collectInterval() / 2
+
+	var due []section
+	var plan []shell.PlanStep
+	var names []string
+	for _, sec := range s.sections() {
+		last, ok := s.lastCollected[sec.name]
+		if ok && now.Sub(last) < sec.interval-slack {
+			continue
+		}
+		due = append(due, sec)
+		plan = append(plan, shell.PlanStep{Name: sec.name, Set: sec.set, Only: sec.only})
+		names = append(names, sec.name)
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	log.Printf("[job] collect: starting (%s)", strings.Join(names, ", "))
+	ctx, cancel := s.scanContext()
+	defer cancel()
+	rep := newReport("collect")
+	collected := make(map[string]bool, len(due))
+
+	for r := range shell.SendPlanContext(ctx, "", s.cfg.OLTUser, s.cfg.OLTPass, plan) {
+		if r.Err != nil {
+			log.Printf("[job] collect: ERROR %s (%d/%d sections): %v", r.Host, len(r.Steps), len(due), r.Err)
+		}
+		rows := 0
+		var saveErr error
+		for _, sec := range due {
+			cmds, ok := r.Steps[sec.name]
+			if !ok {
+				continue
+			}
+			collected[sec.name] = true
+			n, err := sec.save(r, cmds)
+			rows += n
+			if err != nil {
+				saveErr = err
+			}
+		}
+		rep.add(r, rows, saveErr)
+	}
+	for _, sec := range due {
+		if sec.name == "health" {
+			// devices polled over SNMP are left out of the plan
+			if s.pollHealth(ctx, rep) {
+				collected[sec.name] = true
+			}
+		}
+	}
+	s.finish(rep)
+
+	for _, sec := range due {
+		if !collected[sec.name] {
+			log.Printf("[job] collect: %s: no device completed, retrying next tick", sec.name)
+			continue
+		}
+		s.lastCollected[sec.name] = now
+		s.notify(sec.event)
+	}
+	log.Println("[job] collect: done")
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/shell"
+)
+
+// report accumulates the outcome of one job run.
+type report struct {
+	models.JobReport
+}
+
+func newReport(job string) *report {
+	return &report{models.JobReport{Job: job, StartedAt: time.Now()}}
+}
+
+// add counts one device. rows is how many records were stored for it and
+// saveErr is set when they could not be stored.
+func (rep *report) add(r shell.Result, rows int, saveErr error) {
+	rep.Devices++
+	errs := jobErrors(r)
+	if saveErr != nil {
+		errs = append(errs, models.JobError{
+			Host:   r.Host,
+			Device: r.Device,
+			Kind:   "storage",
+			Error:  saveErr.Error(),
+		})
+	}
+	switch {
+	case len(errs) > 0:
+		rep.Failed++
+		for _, e := range errs {
+			rep.Errors = append(rep.Errors, e)
+		}
+	case rows == 0:
+		rep.Empty++
+	default:
+		rep.Succeeded++
+	}
+}
+
+// jobErrors lists every rejected command of r, or the session error when no
+// command got as far as being rejected.
+func jobErrors(r shell.Result) []models.JobError {
+	var out []models.JobError
+	for _, c := range r.Commands {
+		if !c.Failed {
+			continue
+		}
+		out = append(out, models.JobError{
+			Host:    r.Host,
+			Device:  r.Device,
+			Command: c.Command,
+			Kind:    shell.ErrorKind(c.Err),
+			Error:   c.Err.Error(),
+		})
+	}
+	if len(out) == 0 && r.Err != nil {
+		out = append(out, models.JobError{
+			Host:   r.Host,
+			Device: r.Device,
+			Kind:   shell.ErrorKind(r.Err),
+			Error:  r.Err.Error(),
+		})
+	}
+	return out
+}
+
+func (s *Scheduler) finish(rep *report) {
+	rep.FinishedAt = time.Now()
+	if err := s.reportRepo.Create(&rep.JobReport); err != nil {
+		log.Printf("[job] %s: report: %v", rep.Job, err)
+	}
+}
@@ -1,11 +1,13 @@
 package scheduler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,18 +16,29 @@ import (
 	"github.com/Flafl/DevOpsCore/internal/models"
 	"github.com/Flafl/DevOpsCore/internal/repository"
 	"github.com/Flafl/DevOpsCore/internal/shell"
+	"github.com/Flafl/DevOpsCore/internal/snmp"
 	websocket "github.com/Flafl/DevOpsCore/internal/webSocket"
 	"github.com/go-co-op/gocron/v2"
 )
 
 type Scheduler struct {
+	ctx        context.Context
+	sched      gocron.Scheduler
 	cfg        *config.Config
 	hub        *websocket.Hub
 	powerRepo  repository.PowerRepository
 	descRepo   repository.DescriptionRepository
+	statusRepo repository.OntStatusRepository
+	serialRepo repository.OntSerialRepository
 	healthRepo repository.HealthRepository
 	portRepo   repository.PortProtectionRepository
 	backupRepo repository.BackupRepository
+	oltRepo    repository.OltRepository
+	reportRepo repository.JobReportRepository
+	poller     *snmp.Poller
+
+	// lastCollected is when each section last ran in collection plan mode.
+	lastCollected map[string]time.Time
 }
 
 func New(
@@ -33,32 +46,65 @@ func New(
 	hub *websocket.Hub,
 	pr repository.PowerRepository,
 	dr repository.DescriptionRepository,
+	sr repository.OntStatusRepository,
+	snr repository.OntSerialRepository,
 	hr repository.HealthRepository,
 	pp repository.PortProtectionRepository,
 	br repository.BackupRepository,
+	or repository.OltRepository,
+	jr repository.JobReportRepository,
 ) *Scheduler {
 	return &Scheduler{
 		cfg:        cfg,
 		hub:        hub,
 		powerRepo:  pr,
 		descRepo:   dr,
+		statusRepo: sr,
+		serialRepo: snr,
 		healthRepo: hr,
 		portRepo:   pp,
 		backupRepo: br,
+		oltRepo:    or,
+		reportRepo: jr,
+		poller: snmp.NewPoller(snmp.Config{
+			Version:   cfg.SNMPVersion,
+			Community: cfg.SNMPCommunity,
+			Port:      uint16(cfg.SNMPPort),
+			Timeout:   cfg.SNMPTimeout,
+			Retries:   cfg.SNMPRetries,
+			User:      cfg.SNMPUser,
+			AuthProto: cfg.SNMPAuthProto,
+			AuthPass:  cfg.SNMPAuthPass,
+			PrivProto: cfg.SNMPPrivProto,
+			PrivPass:  cfg.SNMPPrivPass,
+		}),
+
+		lastCollected: map[string]time.Time{},
 	}
 }
 
-func (s *Scheduler) Start() {
+// Start schedules every job. Cancelling ctx aborts the scans in flight,
+// including their open SSH sessions.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.ctx = ctx
 	sched, err := gocron.NewScheduler()
 	if err != nil {
 		log.Fatalf("scheduler: %v", err)
 	}
-
-	mustAdd(sched, s.cfg.PowerScanInterval, s.runPowerScan, "power-scan")
-	mustAdd(sched, s.cfg.DescScanInterval, s.runDescScan, "desc-scan")
-	mustAdd(sched, s.cfg.HealthScanInterval, s.runHealthScan, "health-scan")
-	mustAdd(sched, s.cfg.PortScanInterval, s.runPortScan, "port-scan")
+	s.sched = sched
+
+	if s.cfg.CollectionPlan {
+		mustAdd(sched, s.collectInterval(), s.runCollect, "collect")
+	} else {
+		mustAdd(sched, s.cfg.PowerScanInterval, s.runPowerScan, "power-scan")
+		mustAdd(sched, s.cfg.DescScanInterval, s.runDescScan, "desc-scan")
+		mustAdd(sched, s.cfg.HealthScanInterval, s.runHealthScan, "health-scan")
+		mustAdd(sched, s.cfg.PortScanInterval, s.runPortScan, "port-scan")
+	}
 	mustAdd(sched, s.cfg.BackupInterval, s.runBackup, "backup")
+	if s.cfg.OLTsAPIURL != "" && s.cfg.OLTSyncInterval > 0 {
+		mustAdd(sched, s.cfg.OLTSyncInterval, s.runOltSync, "olt-sync")
+	}
 
 	sched.Start()
 	log.Println("scheduler started")
@@ -79,6 +125,21 @@ func (s *Scheduler) Start() {
 	// }()
 }
 
+// Stop waits for running jobs to return and shuts the scheduler down.
+func (s *Scheduler) Stop() {
+	if s.sched == nil {
+		return
+	}
+	if err := s.sched.Shutdown(); err != nil {
+		log.Printf("scheduler: shutdown: %v", err)
+	}
+}
+
+// scanContext bounds one scan job by the configured scan timeout.
+func (s *Scheduler) scanContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(s.ctx, s.cfg.ScanTimeout)
+}
+
 func mustAdd(sched gocron.Scheduler, interval time.Duration, fn func(), name string) {
 	_, err := sched.NewJob(
 		gocron.DurationJob(interval),
@@ -96,201 +157,404 @@ func mustAdd(sched gocron.Scheduler, interval time.Duration, fn func(), name str
 
 func (s *Scheduler) runPowerScan() {
 	log.Println("[job] power-scan: starting")
-	cmd := "show equipment ont optics"
+	ctx, cancel := s.scanContext()
+	defer cancel()
+	rep := newReport("power-scan")
 
-	for r := range shell.SendCommandNokiaOLTs(s.cfg.OLTUser, s.cfg.OLTPass, cmd) {
+	for r := range shell.SendOLTsContext(ctx, "", s.cfg.OLTUser, s.cfg.OLTPass, shell.Driver.OpticsCommands) {
 		if r.Err != nil {
 			log.Printf("[job] power-scan: ERROR %s: %v", r.Host, r.Err)
-			continue
-		}
-		powers := extractor.ExtractAllOntPower(r.Data)
-		if len(powers) == 0 {
-			continue
-		}
-
-		records := make([]models.PowerReading, len(powers))
-		for i, p := range powers {
-			records[i] = models.PowerReading{
-				OntIdx: p.OntIdx,
-				OltRx:  p.OltRx,
+			if !r.Complete() {
+				rep.add(r, 0, nil)
+				continue
 			}
 		}
+		n, err := s.savePower(r, r.Commands)
+		rep.add(r, n, err)
+	}
+	s.finish(rep)
+	s.notify("power_update")
+	log.Println("[job] power-scan: done")
+}
 
-		if err := s.powerRepo.DeleteByHost(r.Host); err != nil {
-			log.Printf("[job] power-scan: delete %s: %v", r.Host, err)
+// extractPowers parses the optics commands of r with their templates, or
+// else with its vendor's extractor.
+func extractPowers(r shell.Result, cmds []shell.CommandResult) []extractor.OntPower {
+	if recs, ok := fromTemplates("power-scan", r, cmds); ok {
+		return extractor.OntPowerFromRecords(slices.Concat(recs...))
+	}
+	var out []extractor.OntPower
+	switch r.Vendor {
+	case "huawei":
+		for i, c := range cmds {
+			out = append(out, extractor.ExtractHuaweiOntPower(c.Command, shell.Output(cmds, i))...)
 		}
-		if err := s.powerRepo.BulkInsert(r.Device, r.Site, r.Host, records); err != nil {
-			log.Printf("[job] power-scan: insert %s: %v", r.Host, err)
+		return out
+	case "zte":
+		for i := range cmds {
+			out = append(out, extractor.ExtractZteOntPower(shell.Output(cmds, i))...)
 		}
+		return out
 	}
-	s.notify("power_update")
-	log.Println("[job] power-scan: done")
+	return extractor.ExtractAllOntPower(shell.JoinedOutput(cmds))
+}
+
+func (s *Scheduler) savePower(r shell.Result, cmds []shell.CommandResult) (int, error) {
+	powers := extractPowers(r, cmds)
+	if len(powers) == 0 {
+		return 0, nil
+	}
+
+	records := make([]models.PowerReading, len(powers))
+	for i, p := range powers {
+		records[i] = models.PowerReading{
+			OntIdx:      p.OntIdx,
+			OltRx:       p.OltRx,
+			OntRx:       p.OntRx,
+			OntTx:       p.OntTx,
+			LaserBias:   p.LaserBias,
+			Temperature: p.Temperature,
+			Voltage:     p.Voltage,
+		}
+	}
+
+	if err := s.powerRepo.DeleteByHost(r.Host); err != nil {
+		log.Printf("[job] power-scan: delete %s: %v", r.Host, err)
+	}
+	if err := s.powerRepo.BulkInsert(r.Device, r.Site, r.Host, records); err != nil {
+		log.Printf("[job] power-scan: insert %s: %v", r.Host, err)
+		return 0, err
+	}
+	return len(records), nil
 }
 
 // --- Description scan job ---
 
 func (s *Scheduler) runDescScan() {
 	log.Println("[job] desc-scan: starting")
-	cmd := "show equipment ont status pon"
+	ctx, cancel := s.scanContext()
+	defer cancel()
+	rep := newReport("desc-scan")
 
-	for r := range shell.SendCommandNokiaOLTs(s.cfg.OLTUser, s.cfg.OLTPass, cmd) {
+	for r := range shell.SendOLTsContext(ctx, "", s.cfg.OLTUser, s.cfg.OLTPass, shell.Driver.StatusCommands) {
 		if r.Err != nil {
 			log.Printf("[job] desc-scan: ERROR %s: %v", r.Host, r.Err)
-			continue
-		}
-		descs := extractor.ExtractAllDesc(r.Data)
-		if len(descs) == 0 {
-			continue
+			if !r.Complete() {
+				rep.add(r, 0, nil)
+				continue
+			}
 		}
+		n, err := s.saveStatus(r, r.Commands)
+		rep.add(r, n, err)
+	}
+	s.finish(rep)
+	s.notify("desc_update")
+	log.Println("[job] desc-scan: done")
+}
 
-		records := make([]models.OntDescription, len(descs))
-		for i, d := range descs {
-			records[i] = models.OntDescription{
-				OntIdx: d.OntIdx,
-				Desc1:  d.Desc1,
-				Desc2:  d.Desc2,
-			}
+// saveStatus stores the descriptions and, on Nokia, the ONT states read by
+// the status commands. Rows count the descriptions.
+func (s *Scheduler) saveStatus(r shell.Result, cmds []shell.CommandResult) (int, error) {
+	n, err := s.saveDesc(r, cmds)
+	if r.Vendor == "nokia" {
+		if serr := s.saveOntStatus(r, cmds); serr != nil && err == nil {
+			err = serr
 		}
+	}
+	return n, err
+}
 
-		if err := s.descRepo.DeleteByHost(r.Host); err != nil {
-			log.Printf("[job] desc-scan: delete %s: %v", r.Host, err)
+func (s *Scheduler) saveOntStatus(r shell.Result, cmds []shell.CommandResult) error {
+	states := extractor.ExtractOntStatus(shell.JoinedOutput(cmds))
+	if len(states) == 0 {
+		// keep the expected serials rather than wiping them on odd output
+		return nil
+	}
+	records := make([]models.OntStatus, len(states))
+	for i, st := range states {
+		records[i] = models.OntStatus{
+			Pon:         st.Pon,
+			OntIdx:      st.OntIdx,
+			Sernum:      st.Sernum,
+			AdminStatus: st.AdminStatus,
+			OperStatus:  st.OperStatus,
+			OltRx:       st.OltRx,
+			DistanceKm:  st.DistanceKm,
 		}
-		if err := s.descRepo.BulkInsert(r.Device, r.Site, r.Host, records); err != nil {
-			log.Printf("[job] desc-scan: insert %s: %v", r.Host, err)
+	}
+	if err := s.statusRepo.Replace(r.Device, r.Site, r.Host, records); err != nil {
+		log.Printf("[job] desc-scan: ont status %s: %v", r.Host, err)
+		return err
+	}
+	s.indexStatusSerials(r, cmds, states)
+	return nil
+}
+
+func (s *Scheduler) saveDesc(r shell.Result, cmds []shell.CommandResult) (int, error) {
+	data := shell.JoinedOutput(cmds)
+	var descs []extractor.OntDesc
+	recs, fromTemplate := fromTemplates("desc-scan", r, cmds)
+	switch {
+	case fromTemplate:
+		descs = extractor.OntDescFromRecords(slices.Concat(recs...))
+	case r.Vendor == "huawei":
+		descs = extractor.ExtractHuaweiDesc(data)
+	case r.Vendor == "zte":
+		descs = extractor.ExtractZteDesc(data)
+	default:
+		descs = extractor.ExtractAllDesc(data)
+	}
+	if len(descs) == 0 {
+		return 0, nil
+	}
+
+	records := make([]models.OntDescription, len(descs))
+	for i, d := range descs {
+		records[i] = models.OntDescription{
+			OntIdx: d.OntIdx,
+			Desc1:  d.Desc1,
+			Desc2:  d.Desc2,
 		}
 	}
-	s.notify("desc_update")
-	log.Println("[job] desc-scan: done")
+
+	if err := s.descRepo.DeleteByHost(r.Host); err != nil {
+		log.Printf("[job] desc-scan: delete %s: %v", r.Host, err)
+	}
+	if err := s.descRepo.BulkInsert(r.Device, r.Site, r.Host, records); err != nil {
+		log.Printf("[job] desc-scan: insert %s: %v", r.Host, err)
+		return 0, err
+	}
+	return len(records), nil
 }
 
 // --- Health scan job ---
 
 func (s *Scheduler) runHealthScan() {
 	log.Println("[job] health-scan: starting")
-	cmds := []string{
-		"show system cpu-load detail",
-		"show core1-uptime",
-		"show equipment temperature",
-	}
+	ctx, cancel := s.scanContext()
+	defer cancel()
+	rep := newReport("health-scan")
 
-	for r := range shell.SendCommandNokiaOLTs(s.cfg.OLTUser, s.cfg.OLTPass, cmds...) {
+	plan := []shell.PlanStep{{Set: onlyVendor(shell.Driver.HealthCommands, healthVendors...), Only: sshHealth}}
+	for r := range shell.SendPlanContext(ctx, "", s.cfg.OLTUser, s.cfg.OLTPass, plan) {
 		if r.Err != nil {
 			log.Printf("[job] health-scan: ERROR %s: %v", r.Host, r.Err)
-			continue
-		}
-		h := extractor.ExtractHealth(r.Data)
-
-		cpuJSON, _ := json.Marshal(h.CpuLoads)
-		tempJSON, _ := json.Marshal(h.Temperatures)
-
-		var cpuSlice models.JSONSlice
-		var tempSlice models.JSONSlice
-		json.Unmarshal(cpuJSON, &cpuSlice)
-		json.Unmarshal(tempJSON, &tempSlice)
-
-		record := &models.OltHealth{
-			Device:       r.Device,
-			Site:         r.Site,
-			Host:         r.Host,
-			Uptime:       h.Uptime,
-			CpuLoads:     cpuSlice,
-			Temperatures: tempSlice,
-			MeasuredAt:   time.Now(),
-		}
-
-		if err := s.healthRepo.Upsert(record); err != nil {
-			log.Printf("[job] health-scan: upsert %s: %v", r.Host, err)
+			if !r.Complete() {
+				rep.add(r, 0, nil)
+				continue
+			}
 		}
+		n, err := s.saveHealth(r, r.Commands)
+		rep.add(r, n, err)
 	}
+	s.pollHealth(ctx, rep)
+	s.finish(rep)
 	s.notify("health_update")
 	log.Println("[job] health-scan: done")
 }
 
+// saveHealth parses each health command on its own, in the order given by
+// Driver.HealthCommands.
+func (s *Scheduler) saveHealth(r shell.Result, cmds []shell.CommandResult) (int, error) {
+	if recs, ok := fromTemplates("health-scan", r, cmds); ok && len(recs) == 3 {
+		return s.storeHealth(r, extractor.HealthFromRecords(recs[0], recs[1], recs[2]), nil, "ssh")
+	}
+	parse := extractor.ExtractHealthFrom
+	if r.Vendor == "zte" {
+		parse = extractor.ExtractZteHealthFrom
+	}
+	h := parse(
+		shell.Output(cmds, 0),
+		shell.Output(cmds, 1),
+		shell.Output(cmds, 2),
+	)
+	return s.storeHealth(r, h, nil, "ssh")
+}
+
+// storeHealth upserts the health of r's device. boards is only known when
+// polling over SNMP.
+func (s *Scheduler) storeHealth(r shell.Result, h extractor.Health, boards []snmp.Board, source string) (int, error) {
+	record := &models.OltHealth{
+		Device:       r.Device,
+		Site:         r.Site,
+		Host:         r.Host,
+		Uptime:       h.Uptime,
+		CpuLoads:     jsonSlice(h.CpuLoads),
+		Temperatures: jsonSlice(h.Temperatures),
+		Boards:       jsonSlice(boards),
+		Source:       source,
+		MeasuredAt:   time.Now(),
+	}
+
+	if err := s.healthRepo.Upsert(record); err != nil {
+		log.Printf("[job] health-scan: upsert %s: %v", r.Host, err)
+		return 0, err
+	}
+	return len(h.CpuLoads) + len(h.Temperatures) + len(boards), nil
+}
+
+// jsonSlice converts a slice of structs to the generic form stored in JSONB
+// columns.
+func jsonSlice(v any) models.JSONSlice {
+	b, _ := json.Marshal(v)
+	var out models.JSONSlice
+	json.Unmarshal(b, &out)
+	return out
+}
+
 // --- Port protection scan job ---
 
 func (s *Scheduler) runPortScan() {
 	log.Println("[job] port-scan: starting")
-	cmd := "show port-protection"
+	ctx, cancel := s.scanContext()
+	defer cancel()
+	rep := newReport("port-scan")
 
-	for r := range shell.SendCommandNokiaOLTs(s.cfg.OLTUser, s.cfg.OLTPass, cmd) {
+	for r := range shell.SendOLTsContext(ctx, "nokia", s.cfg.OLTUser, s.cfg.OLTPass, shell.Driver.PortProtectionCommands) {
 		if r.Err != nil {
 			log.Printf("[job] port-scan: ERROR %s: %v", r.Host, r.Err)
-			continue
-		}
-		ports := extractor.ExtractPortProtection(r.Data)
-
-		var filtered []models.PortProtectionRecord
-		for _, p := range ports {
-			if strings.Contains(p.PortState, "down") || strings.Contains(p.PairedState, "down") {
-				filtered = append(filtered, models.PortProtectionRecord{
-					Port:        p.Port,
-					PortState:   p.PortState,
-					PairedState: p.PairedState,
-					SwoReason:   p.SwoReason,
-					NumSwo:      p.NumSwo,
-				})
+			if !r.Complete() {
+				rep.add(r, 0, nil)
+				continue
 			}
 		}
+		n, err := s.savePorts(r, r.Commands)
+		rep.add(r, n, err)
+	}
+	s.finish(rep)
+	s.notify("port_update")
+	log.Println("[job] port-scan: done")
+}
 
-		if err := s.portRepo.DeleteByHost(r.Host); err != nil {
-			log.Printf("[job] port-scan: delete %s: %v", r.Host, err)
+func (s *Scheduler) savePorts(r shell.Result, cmds []shell.CommandResult) (int, error) {
+	var ports []extractor.PortProtection
+	if recs, ok := fromTemplates("port-scan", r, cmds); ok {
+		ports = extractor.PortProtectionFromRecords(slices.Concat(recs...))
+	} else {
+		ports = extractor.ExtractPortProtection(shell.JoinedOutput(cmds))
+	}
+
+	var filtered []models.PortProtectionRecord
+	for _, p := range ports {
+		if strings.Contains(p.PortState, "down") || strings.Contains(p.PairedState, "down") {
+			filtered = append(filtered, models.PortProtectionRecord{
+				Port:        p.Port,
+				PortState:   p.PortState,
+				PairedState: p.PairedState,
+				SwoReason:   p.SwoReason,
+				NumSwo:      p.NumSwo,
+			})
 		}
-		if len(filtered) > 0 {
-			if err := s.portRepo.BulkInsert(r.Device, r.Site, r.Host, filtered); err != nil {
-				log.Printf("[job] port-scan: insert %s: %v", r.Host, err)
-			}
+	}
+
+	if err := s.portRepo.DeleteByHost(r.Host); err != nil {
+		log.Printf("[job] port-scan: delete %s: %v", r.Host, err)
+	}
+	if len(filtered) > 0 {
+		if err := s.portRepo.BulkInsert(r.Device, r.Site, r.Host, filtered); err != nil {
+			log.Printf("[job] port-scan: insert %s: %v", r.Host, err)
+			return 0, err
 		}
 	}
-	s.notify("port_update")
-	log.Println("[job] port-scan: done")
+	// every parsed group counts, not just the ones stored as down
+	return len(ports), nil
 }
 
 // --- Backup job ---
 
 func (s *Scheduler) runBackup() {
 	log.Println("[job] backup: starting")
-	cmd := "info configure flat"
+	ctx, cancel := s.scanContext()
+	defer cancel()
+	// backups hold a device for minutes; let scans and users go first
+	ctx = shell.WithPriority(ctx, shell.PriorityLow)
+	rep := newReport("backup")
 
-	for r := range shell.SendCommandNokiaOLTs(s.cfg.OLTUser, s.cfg.OLTPass, cmd) {
+	for r := range shell.SendOLTsContext(ctx, "", s.cfg.OLTUser, s.cfg.OLTPass, shell.Driver.BackupCommands) {
 		if r.Err != nil {
 			log.Printf("[job] backup: ERROR %s: %v", r.Host, r.Err)
-			continue
+			if !r.Complete() {
+				rep.add(r, 0, nil)
+				continue
+			}
 		}
+		n, err := s.saveBackup(r)
+		rep.add(r, n, err)
+	}
+	s.finish(rep)
+	s.notify("backup_update")
+	log.Println("[job] backup: done")
+}
 
-		site := strings.ReplaceAll(r.Site, "/", "-")
-		if site == "" {
-			site = "unknown"
-		}
+// saveBackup writes the cleaned config of r to disk and records it. It
+// returns 0 when the device returned an empty config.
+func (s *Scheduler) saveBackup(r shell.Result) (int, error) {
+	site := strings.ReplaceAll(r.Site, "/", "-")
+	if site == "" {
+		site = "unknown"
+	}
 
-		folder := filepath.Join("backups", site, time.Now().Format("2006-01-02"))
-		if err := os.MkdirAll(folder, 0o755); err != nil {
-			log.Printf("[job] backup: mkdir %s: %v", folder, err)
-			continue
-		}
+	folder := filepath.Join("backups", site, time.Now().Format("2006-01-02"))
+	if err := os.MkdirAll(folder, 0o755); err != nil {
+		log.Printf("[job] backup: mkdir %s: %v", folder, err)
+		return 0, err
+	}
+
+	var cleaned string
+	switch r.Vendor {
+	case "huawei":
+		cleaned = extractor.CleanHuaweiBackupOutput(r.Data)
+	case "zte":
+		cleaned = extractor.CleanZteBackupOutput(r.Data)
+	default:
+		cleaned = extractor.CleanBackupOutput(r.Data)
+	}
+	if strings.TrimSpace(cleaned) == "" {
+		return 0, nil
+	}
 
-		cleaned := extractor.CleanBackupOutput(r.Data)
+	name := strings.ReplaceAll(r.Device, "/", "-")
+	filename := fmt.Sprintf("%s_%s.txt", name, r.Host)
+	path := filepath.Join(folder, filename)
 
-		name := strings.ReplaceAll(r.Device, "/", "-")
-		filename := fmt.Sprintf("%s_%s.txt", name, r.Host)
-		path := filepath.Join(folder, filename)
+	if err := os.WriteFile(path, []byte(cleaned), 0o644); err != nil {
+		log.Printf("[job] backup: write %s: %v", path, err)
+		return 0, err
+	}
+	log.Printf("[job] backup: saved %s", path)
+
+	if err := s.backupRepo.Create(&models.OltBackups{
+		Device:   r.Device,
+		Site:     site,
+		Host:     r.Host,
+		Vendor:   r.Vendor,
+		FilePath: path,
+	}); err != nil {
+		log.Printf("[job] backup: db %s: %v", r.Host, err)
+		return 0, err
+	}
+	if r.Vendor == "nokia" {
+		s.indexConfigSerials(r, extractor.ParseFlatConfig(cleaned))
+	}
+	return 1, nil
+}
 
-		if err := os.WriteFile(path, []byte(cleaned), 0o644); err != nil {
-			log.Printf("[job] backup: write %s: %v", path, err)
-			continue
-		}
-		log.Printf("[job] backup: saved %s", path)
-
-		if err := s.backupRepo.Create(&models.OltBackups{
-			Device:   r.Device,
-			Site:     site,
-			Host:     r.Host,
-			FilePath: path,
-		}); err != nil {
-			log.Printf("[job] backup: db %s: %v", r.Host, err)
-		}
+// --- OLT inventory sync job ---
+
+func (s *Scheduler) runOltSync() {
+	log.Println("[job] olt-sync: starting")
+	olts, err := shell.FetchOLTs(s.cfg.OLTsAPIURL)
+	if err != nil {
+		log.Printf("[job] olt-sync: %v", err)
+		return
 	}
-	s.notify("backup_update")
-	log.Println("[job] backup: done")
+	created, updated, skipped, err := s.oltRepo.Upsert(olts.ToModels(), false)
+	if err != nil {
+		log.Printf("[job] olt-sync: upsert: %v", err)
+		return
+	}
+	s.notify("olt_update")
+	log.Printf("[job] olt-sync: done (%d created, %d updated, %d skipped)", created, updated, skipped)
 }
 
 // --- notify ---
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/extractor"
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/shell"
+)
+
+// indexStatusSerials records the serials of a Nokia status scan, with the
+// descriptions read by the same commands.
+func (s *Scheduler) indexStatusSerials(r shell.Result, cmds []shell.CommandResult, states []extractor.OntStatus) {
+	descs := map[string]extractor.OntDesc{}
+	for _, d := range extractor.ExtractAllDesc(shell.JoinedOutput(cmds)) {
+		descs[d.OntIdx] = d
+	}
+
+	now := time.Now()
+	var rows []models.OntSerial
+	for _, st := range states {
+		row, ok := serialRow(r, st.Sernum, st.OntIdx, "status", now)
+		if !ok {
+			continue
+		}
+		row.Desc1 = descs[st.OntIdx].Desc1
+		row.Desc2 = descs[st.OntIdx].Desc2
+		row.AdminState = st.AdminStatus
+		row.OperState = st.OperStatus
+		rows = append(rows, row)
+	}
+	if err := s.serialRepo.Upsert(rows); err != nil {
+		log.Printf("[job] desc-scan: serial index %s: %v", r.Host, err)
+	}
+}
+
+// indexConfigSerials records the serials provisioned in a Nokia backup.
+func (s *Scheduler) indexConfigSerials(r shell.Result, cfg *extractor.FlatConfig) {
+	now := time.Now()
+	var rows []models.OntSerial
+	for _, o := range cfg.Onts {
+		row, ok := serialRow(r, o.Sernum, o.OntIdx, "config", now)
+		if !ok {
+			continue
+		}
+		row.Desc1 = o.Desc1
+		row.Desc2 = o.Desc2
+		row.AdminState = o.AdminState
+		rows = append(rows, row)
+	}
+	if err := s.serialRepo.Upsert(rows); err != nil {
+		log.Printf("[job] backup: serial index %s: %v", r.Host, err)
+	}
+}
+
+// serialRow starts the index row for sernum at ontIdx on r's device. ONTs
+// provisioned without a serial are left out.
+func serialRow(r shell.Result, sernum, ontIdx, source string, seen time.Time) (models.OntSerial, bool) {
+	sernum = extractor.NormalizeSerial(sernum)
+	if sernum == "" || ontIdx == "" {
+		return models.OntSerial{}, false
+	}
+	return models.OntSerial{
+		Sernum:   sernum,
+		Vendor:   extractor.SerialVendor(sernum),
+		Device:   r.Device,
+		Site:     r.Site,
+		Host:     r.Host,
+		OntIdx:   ontIdx,
+		Source:   source,
+		LastSeen: seen,
+	}, true
+}
@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/Flafl/DevOpsCore/internal/extractor"
+	"github.com/Flafl/DevOpsCore/internal/shell"
+	"github.com/Flafl/DevOpsCore/internal/snmp"
+)
+
+// snmpWorkers bounds the devices polled at once. Polls are cheap UDP round
+// trips and do not go through the SSH session broker.
+const snmpWorkers = 16
+
+// sshHealth selects the devices whose health is scraped over SSH.
+func sshHealth(olt shell.OLT) bool {
+	return olt.HealthSource != "snmp"
+}
+
+// pollHealth polls every enabled OLT whose health source is "snmp" and adds
+// each one to rep. It reports whether any of them answered.
+func (s *Scheduler) pollHealth(ctx context.Context, rep *report) (polledAny bool) {
+	olts, err := shell.LoadOLTs()
+	if err != nil {
+		log.Printf("[job] health-scan: %v", err)
+		return false
+	}
+
+	type polled struct {
+		r shell.Result
+		h snmp.Health
+	}
+	results := make(chan polled)
+	sem := make(chan struct{}, snmpWorkers)
+	var wg sync.WaitGroup
+
+	for _, olt := range olts {
+		if sshHealth(olt) {
+			continue
+		}
+		vendor, _ := shell.CanonicalVendor(olt.Vendor)
+		// the inventory host may carry the SSH port
+		host := olt.Ip
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			r := shell.Result{Device: olt.Name, Site: olt.Site, Host: olt.Ip, Vendor: vendor}
+			h, err := s.poller.Poll(ctx, host, vendor)
+			r.Err = err
+			results <- polled{r: r, h: h}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for p := range results {
+		if p.r.Err != nil {
+			log.Printf("[job] health-scan: ERROR %s (snmp): %v", p.r.Host, p.r.Err)
+			rep.add(p.r, 0, nil)
+			continue
+		}
+		polledAny = true
+		h := extractor.Health{Uptime: p.h.Uptime, CpuLoads: p.h.CpuLoads, Temperatures: p.h.Temperatures}
+		n, err := s.storeHealth(p.r, h, p.h.Boards, "snmp")
+		rep.add(p.r, n, err)
+	}
+	return polledAny
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"log"
+
+	"github.com/Flafl/DevOpsCore/internal/extractor"
+	"github.com/Flafl/DevOpsCore/internal/shell"
+)
+
+// fromTemplates parses each of cmds with the TextFSM template for r's vendor
+// and firmware. It reports false, so the caller uses its built-in parser,
+// unless every command has a template that parsed; a failing template is
+// logged under job.
+func fromTemplates(job string, r shell.Result, cmds []shell.CommandResult) ([][]extractor.Record, bool) {
+	if len(cmds) == 0 {
+		return nil, false
+	}
+	out := make([][]extractor.Record, len(cmds))
+	for i, c := range cmds {
+		recs, ok, err := extractor.ParseCommand(r.Vendor, r.Firmware, c.Command, shell.Output(cmds, i))
+		if err != nil {
+			log.Printf("[job] %s: template %s on %s: %v", job, c.Command, r.Host, err)
+			return nil, false
+		}
+		if !ok {
+			return nil, false
+		}
+		out[i] = recs
+	}
+	return out, true
+}
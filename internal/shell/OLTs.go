@@ -2,12 +2,14 @@ package shell
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
+	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/Flafl/DevOpsCore/internal/models"
 )
 
 type OLT struct {
@@ -15,42 +17,160 @@ type OLT struct {
 	Name   string `json:"name"`
 	Site   string `json:"site"`
 	Vendor string `json:"vendor"`
+	Model  string `json:"model"`
+	// Firmware is the software release, when known.
+	Firmware string `json:"firmware,omitempty"`
+	// Transport is "ssh" (default) or "telnet".
+	Transport string `json:"transport,omitempty"`
+	// HealthSource is "ssh" (default) or "snmp".
+	HealthSource string `json:"health_source,omitempty"`
 }
 type OLTs []OLT
 
-func OLTsData() (nokia OLTs, huawei OLTs) {
-	_ = godotenv.Load("../.env")
+// Inventory is the source of OLTs the SendCommand*OLTs helpers iterate over.
+// repository.OltRepository satisfies it.
+type Inventory interface {
+	GetEnabled() ([]models.Olt, error)
+}
+
+var inventory Inventory
+
+// SetInventory installs the inventory used by LoadOLTs. It must be called
+// once at startup before any scan runs.
+func SetInventory(inv Inventory) {
+	inventory = inv
+}
+
+// CredentialResolver picks the SSH account for one OLT. An empty username
+// with a nil error means no account is assigned and the caller's credentials
+// are used. credentials.Store satisfies it.
+type CredentialResolver interface {
+	Resolve(host, site string) (username, password string, err error)
+}
+
+var credentialResolver CredentialResolver
+
+// SetCredentialResolver installs the per-device credential lookup used by
+// SendOLTs. Without one every device gets the credentials passed in.
+func SetCredentialResolver(r CredentialResolver) {
+	credentialResolver = r
+}
 
-	res, err := http.Get(os.Getenv("OLTS_API_ENV"))
+// credentialsFor returns the account for olt, falling back to user/pass.
+func credentialsFor(olt OLT, user, pass string) (string, string, error) {
+	if credentialResolver == nil {
+		return user, pass, nil
+	}
+	u, p, err := credentialResolver.Resolve(olt.Ip, olt.Site)
 	if err != nil {
-		panic(err)
+		return "", "", fmt.Errorf("shell: credentials for %s: %w", olt.Ip, err)
+	}
+	if u == "" {
+		return user, pass, nil
+	}
+	return u, p, nil
+}
+
+// LoadOLTs returns every enabled OLT from the inventory.
+func LoadOLTs() (OLTs, error) {
+	if inventory == nil {
+		return nil, errors.New("shell: no OLT inventory configured")
+	}
+	rows, err := inventory.GetEnabled()
+	if err != nil {
+		return nil, fmt.Errorf("shell: load inventory: %w", err)
+	}
+
+	out := make(OLTs, 0, len(rows))
+	for _, r := range rows {
+		if r.Host == "" {
+			continue
+		}
+		out = append(out, OLT{
+			Ip:           r.Host,
+			Name:         r.Name,
+			Site:         r.Site,
+			Vendor:       strings.ToLower(r.Vendor),
+			Model:        r.DeviceModel,
+			Firmware:     r.Firmware,
+			Transport:    strings.ToLower(r.Transport),
+			HealthSource: strings.ToLower(r.HealthSource),
+		})
+	}
+	return out, nil
+}
+
+// FetchOLTs downloads the device list from the external OLT API. It is only
+// used as an optional sync source for the inventory.
+func FetchOLTs(url string) (OLTs, error) {
+	if url == "" {
+		return nil, errors.New("shell: OLT API url not configured")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	res, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("shell: fetch OLTs: %w", err)
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("shell: fetch OLTs: unexpected status %s", res.Status)
+	}
+
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("shell: fetch OLTs: %w", err)
 	}
 
 	var data OLTs
 	if err := json.Unmarshal(body, &data); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("shell: decode OLTs: %w", err)
 	}
 
+	out := data[:0]
 	for _, olt := range data {
 		if olt.Ip == "" {
 			continue
 		}
-
-		if strings.HasPrefix(olt.Ip, "10.90.3.") ||
-			olt.Ip == "10.250.0.178" ||
-			olt.Ip == "10.202.160.3" ||
-			olt.Ip == "10.80.2.161" {
-			huawei = append(huawei, olt)
-		} else {
-			nokia = append(nokia, olt)
+		if olt.Vendor == "" {
+			olt.Vendor = legacyVendor(olt.Ip)
 		}
+		olt.Vendor = strings.ToLower(olt.Vendor)
+		out = append(out, olt)
+	}
+	return out, nil
+}
+
+// legacyVendor reproduces the IP based split the external API relied on
+// before it carried a vendor for every device.
+func legacyVendor(ip string) string {
+	if strings.HasPrefix(ip, "10.90.3.") ||
+		ip == "10.250.0.178" ||
+		ip == "10.202.160.3" ||
+		ip == "10.80.2.161" {
+		return "huawei"
 	}
+	return "nokia"
+}
 
-	return nokia, huawei
+// ToModels converts fetched OLTs into inventory rows. An OLT without a
+// vendor gets the one legacyVendor gives its IP.
+func (o OLTs) ToModels() []models.Olt {
+	out := make([]models.Olt, 0, len(o))
+	for _, olt := range o {
+		vendor := strings.ToLower(olt.Vendor)
+		if vendor == "" {
+			vendor = legacyVendor(olt.Ip)
+		}
+		out = append(out, models.Olt{
+			Name:        olt.Name,
+			Host:        olt.Ip,
+			Site:        olt.Site,
+			Vendor:      vendor,
+			DeviceModel: olt.Model,
+			Enabled:     true,
+		})
+	}
+	return out
 }
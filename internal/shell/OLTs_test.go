@@ -0,0 +1,24 @@
+package shell
+
+import "testing"
+
+func TestOLTsToModelsDefaultsVendor(t *testing.T) {
+	olts := OLTs{
+		{Ip: "10.1.1.1", Name: "a", Vendor: "ZTE"},
+		{Ip: "10.1.1.2", Name: "b"},
+		{Ip: "10.90.3.7", Name: "c"},
+	}
+	want := []string{"zte", "nokia", "huawei"}
+	got := olts.ToModels()
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(got), len(want))
+	}
+	for i, m := range got {
+		if m.Vendor != want[i] {
+			t.Errorf("%s: vendor %q, want %q", m.Host, m.Vendor, want[i])
+		}
+		if !m.Enabled {
+			t.Errorf("%s: not enabled", m.Host)
+		}
+	}
+}
@@ -1,126 +1,196 @@
 package shell
 
 import (
-	"regexp"
+	"context"
+	"errors"
+	"log"
+	"strings"
 	"sync"
 	"time"
-
-	"github.com/scrapli/scrapligo/driver/generic"
-	"github.com/scrapli/scrapligo/driver/options"
-	"github.com/scrapli/scrapligo/transport"
 )
 
 type Result struct {
 	Device string
 	Site   string
 	Host   string
-	Data   string
-	Err    error
+	Vendor string
+	// Firmware is the device's release from the inventory, if known.
+	Firmware string
+	// Data is the joined output of every command, for single-command
+	// callers. Parsers should read Commands or Steps instead so one
+	// command's banner or alarm lines never reach another's parser.
+	Data string
+	Err  error
+	// Commands lists every command sent, in order. When Err is set the last
+	// entry is the one that failed, if the session got that far.
+	Commands []CommandResult
+	// Steps holds the commands of each named step of a collection plan.
+	// When the session failed it only has the steps that completed.
+	Steps map[string][]CommandResult
 }
 
-func NkSendCommandOLT(host, user, pass string, cmds ...string) (string, error) {
+// CommandResult is the outcome of one command in a session.
+type CommandResult struct {
+	Command string
+	// Output is what parsers read: the driver has removed the echo, the
+	// prompt, carriage returns and ANSI sequences.
+	Output string
+	// Raw is the output as the device sent it.
+	Raw     string
+	Elapsed time.Duration
+	Failed  bool
+	Err     error
 
-	// init new device
-	driver, err := generic.NewDriver(
-		host,
-		options.WithAuthNoStrictKey(),
-		options.WithAuthUsername(user),
-		options.WithAuthPassword(pass),
-		// options.WithChannelLog(os.Stdout),
-		// options.WithOnOpen(func(d *generic.Driver) error {
-		// 	_,_ = d.SendCommand("screen-length 0 temporary")
-		// 	return nil
-		// }),
-		options.WithPromptPattern(regexp.MustCompile(`(?m)(>#)\s*$`)),
-		options.WithTransportType(transport.StandardTransport),
-		options.WithSSHConfigFile(""),
-		options.WithTimeoutOps(120*time.Minute),
-		options.WithTermWidth(511),
-	)
-	if err != nil {
-		return "", err
-	}
+	// origin is the index of the requested command this one was sent for:
+	// itself, or the command whose output produced it as a follow-up.
+	origin int
+}
 
-	//openning a session
-	if err := driver.Open(); err != nil {
-		return "", err
+// Complete reports whether the session ran through every command, even if
+// the device rejected some of them, so the outputs of the others can be used.
+func (r Result) Complete() bool {
+	var ce *CommandError
+	return r.Err == nil || errors.As(r.Err, &ce)
+}
+
+// Output returns the output of cmds[i], or "" when there is no such
+// command, e.g. because the session failed before reaching it.
+func Output(cmds []CommandResult, i int) string {
+	if i < 0 || i >= len(cmds) || cmds[i].Failed {
+		return ""
 	}
-	defer driver.Close()
+	return cmds[i].Output
+}
 
-	if len(cmds) == 1 {
-		r, err := driver.SendCommand(cmds[0])
-		if err != nil {
-			return "", err
+// JoinedOutput joins the outputs of cmds, skipping failed commands.
+func JoinedOutput(cmds []CommandResult) string {
+	parts := make([]string, 0, len(cmds))
+	for _, c := range cmds {
+		if !c.Failed {
+			parts = append(parts, c.Output)
 		}
-		return r.Result, err
+	}
+	return strings.Join(parts, "\n")
+}
 
-	} else {
-		rs, err := driver.SendCommands(cmds)
-		if err != nil {
-			return "", err
+func NkSendCommandOLT(host, user, pass string, cmds ...string) (string, error) {
+	return NkSendCommandOLTContext(context.Background(), host, user, pass, cmds...)
+}
+
+// NkSendCommandOLTContext is NkSendCommandOLT bounded by ctx and by the
+// per-command timeouts. host is looked up in the inventory so its
+// credentials, transport and jump route apply.
+func NkSendCommandOLTContext(ctx context.Context, host, user, pass string, cmds ...string) (string, error) {
+	return sendCommandOLT(ctx, nokiaISAM{}, lookupOLT(host, "nokia"), user, pass, cmds...)
+}
+
+func HwSendCommandOLT(host, user, pass string, cmds ...string) (string, error) {
+	return HwSendCommandOLTContext(context.Background(), host, user, pass, cmds...)
+}
+
+// HwSendCommandOLTContext is HwSendCommandOLT bounded by ctx and by the
+// per-command timeouts. host is looked up in the inventory so its
+// credentials, transport and jump route apply.
+func HwSendCommandOLTContext(ctx context.Context, host, user, pass string, cmds ...string) (string, error) {
+	return sendCommandOLT(ctx, huaweiMA5800{}, lookupOLT(host, "huawei"), user, pass, cmds...)
+}
+
+// lookupOLT returns the enabled inventory OLT at host, or a bare OLT of
+// vendor for an address the inventory does not list.
+func lookupOLT(host, vendor string) OLT {
+	olts, err := LoadOLTs()
+	if err != nil && inventory != nil {
+		log.Printf("shell: %v", err)
+	}
+	for _, olt := range olts {
+		if olt.Ip == host {
+			return olt
 		}
-		return rs.JoinedResult(), err
 	}
+	return OLT{Ip: host, Vendor: vendor}
+}
 
+// SendOLTs runs commands on every enabled OLT of vendor (all vendors when
+// vendor is empty). The commands for each device come from its driver via
+// set, so a single call can cover a mixed fleet.
+func SendOLTs(vendor, username, password string, set CommandSet) <-chan Result {
+	return SendOLTsContext(context.Background(), vendor, username, password, set)
 }
 
-func HwSendCommandOLT(host, user, pass string, cmds ...string) (string, error) {
-	driver, err := generic.NewDriver(
-		host,
-		options.WithAuthNoStrictKey(),
-		options.WithAuthUsername(user),
-		options.WithAuthPassword(pass),
-		options.WithPromptPattern(regexp.MustCompile(`(?m)[<>]\S+[<>]\s*$`)),
-		options.WithTransportType(transport.StandardTransport),
-		options.WithSSHConfigFile(""),
-		options.WithTimeoutOps(90*time.Minute),
-		options.WithTermWidth(511),
-		options.WithOnOpen(func(d *generic.Driver) error {
-			_, _ = d.SendCommand("screen-length 0 temporary")
-			return nil
-		}),
-	)
+// SendOLTsContext is SendOLTs bounded by ctx. Sessions are handed out by
+// the shared session broker at the priority carried by ctx (see
+// WithPriority). Devices still waiting for a slot when ctx ends are reported
+// with ctx.Err(); sessions already running are torn down.
+func SendOLTsContext(ctx context.Context, vendor, username, password string, set CommandSet) <-chan Result {
+	return SendPlanContext(ctx, vendor, username, password, []PlanStep{{Set: set}})
+}
+
+// PlanStep is one named set of commands in a collection plan.
+type PlanStep struct {
+	Name string
+	Set  CommandSet
+	// Only, when set, limits the step to the devices it accepts.
+	Only func(OLT) bool
+}
+
+// SendPlanContext runs every step of plan on each enabled OLT of vendor in a
+// single session per device, so one login yields a consistent snapshot of
+// everything that is due. Each Result carries the commands of each step in
+// Steps; a command shared by several steps is only sent once.
+func SendPlanContext(ctx context.Context, vendor, username, password string, plan []PlanStep) <-chan Result {
+	olts, err := LoadOLTs()
 	if err != nil {
-		return "", err
+		log.Printf("shell: %v", err)
 	}
 
-	if err := driver.Open(); err != nil {
-		return "", err
+	type job struct {
+		olt    OLT
+		driver Driver
 	}
-	defer driver.Close()
-
-	if len(cmds) == 1 {
-		r, err := driver.SendCommand(cmds[0])
+	var jobs []job
+	for _, olt := range olts {
+		d, err := DriverFor(olt)
 		if err != nil {
-			return "", err
+			log.Printf("shell: skip %s: %v", olt.Ip, err)
+			continue
 		}
-		return r.Result, err
-	} else {
-		rs, err := driver.SendCommands(cmds)
-		if err != nil {
-			return "", err
+		if vendor != "" && d.Vendor() != vendor {
+			continue
 		}
-		return rs.JoinedResult(), err
+		jobs = append(jobs, job{olt: olt, driver: d})
 	}
-}
 
-func SendCommandNokiaOLTs(username, password string, cmds ...string) <-chan Result {
-	nokia, _ := OLTsData()
-	results := make(chan Result, len(nokia))
+	results := make(chan Result, len(jobs))
 	var wg sync.WaitGroup
 
-	parallelSessions := make(chan struct{}, 33)
-
-	for _, olt := range nokia {
-		olt := olt
+	for _, j := range jobs {
+		j := j
+		cmds, steps := planCommands(j.driver, j.olt, plan)
+		if len(cmds) == 0 {
+			continue
+		}
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			parallelSessions <- struct{}{}
-			defer func() { <-parallelSessions }()
+			r := Result{
+				Device:   j.olt.Name,
+				Site:     j.olt.Site,
+				Host:     j.olt.Ip,
+				Vendor:   j.driver.Vendor(),
+				Firmware: j.olt.Firmware,
+			}
 
-			data, err := NkSendCommandOLT(olt.Ip, username, password, cmds...)
-			results <- Result{Device: olt.Name, Site: olt.Site, Host: olt.Ip, Data: data, Err: err}
+			r.Commands, r.Err = sendWithRetry(ctx, j.driver, j.olt, username, password, cmds...)
+			if r.Complete() {
+				r.Data = JoinedOutput(r.Commands)
+			}
+			r.Steps = make(map[string][]CommandResult, len(steps))
+			for name, idx := range steps {
+				if step, ok := pickCommands(r.Commands, idx); ok {
+					r.Steps[name] = step
+				}
+			}
+			results <- r
 		}()
 	}
 	go func() {
@@ -131,53 +201,125 @@ func SendCommandNokiaOLTs(username, password string, cmds ...string) <-chan Resu
 	return results
 }
 
-func SendCommandHuaweiOLTs(username, password string, cmds ...string) <-chan Result {
-	_, huawei := OLTsData()
-	results := make(chan Result, len(huawei))
-	var wg sync.WaitGroup
-	parallelSessions := make(chan struct{}, 33)
+// planCommands flattens plan for d and olt into the commands to send, in
+// order and without repeats, and the indexes of each step's commands in that
+// list.
+func planCommands(d Driver, olt OLT, plan []PlanStep) ([]string, map[string][]int) {
+	var cmds []string
+	pos := map[string]int{}
+	steps := make(map[string][]int, len(plan))
+	for _, step := range plan {
+		if step.Only != nil && !step.Only(olt) {
+			continue
+		}
+		var idx []int
+		for _, cmd := range step.Set(d) {
+			i, ok := pos[cmd]
+			if !ok {
+				i = len(cmds)
+				pos[cmd] = i
+				cmds = append(cmds, cmd)
+			}
+			idx = append(idx, i)
+		}
+		if len(idx) > 0 {
+			steps[step.Name] = idx
+		}
+	}
+	return cmds, steps
+}
 
-	for _, olt := range huawei {
-		olt := olt
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			parallelSessions <- struct{}{}
-			defer func() { <-parallelSessions }()
+// pickCommands returns the commands sent for the requested commands at idx,
+// follow-ups included, or reports false if any of them was never sent or
+// ended the session. Commands the device rejected are kept, marked Failed.
+func pickCommands(cmds []CommandResult, idx []int) ([]CommandResult, bool) {
+	want := make(map[int]bool, len(idx))
+	for _, i := range idx {
+		want[i] = false
+	}
+	var out []CommandResult
+	for _, c := range cmds {
+		if _, ok := want[c.origin]; !ok {
+			continue
+		}
+		var ce *CommandError
+		if c.Failed && !errors.As(c.Err, &ce) {
+			return nil, false
+		}
+		want[c.origin] = true
+		out = append(out, c)
+	}
+	for _, seen := range want {
+		if !seen {
+			return nil, false
+		}
+	}
+	return out, true
+}
 
-			data, err := HwSendCommandOLT(olt.Ip, username, password, cmds...)
-			results <- Result{Device: olt.Name, Site: olt.Site, Host: olt.Ip, Data: data, Err: err}
-		}()
+// sendWithRetry runs cmds on olt under the retry policy, skipping the device
+// while its circuit breaker is open. user and pass are only used when no
+// credential set applies to olt. On error the commands of the last attempt
+// are returned.
+func sendWithRetry(ctx context.Context, d Driver, olt OLT, user, pass string, cmds ...string) ([]CommandResult, error) {
+	user, pass, err := credentialsFor(olt, user, pass)
+	if err != nil {
+		return nil, err
 	}
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-	return results
+	ctx, err = withDialPlan(ctx, olt)
+	if err != nil {
+		return nil, err
+	}
+	if err := breakers.allow(olt); err != nil {
+		return nil, err
+	}
+
+	var results []CommandResult
+	err = withRetry(ctx, currentRetryPolicy(), func() error {
+		var err error
+		results, err = runCommands(ctx, d, olt.Ip, user, pass, cmds...)
+		return err
+	})
+	var ce *CommandError
+	switch {
+	case err != nil && errors.As(err, &ce):
+		// the device answered, it just did not like a command
+		breakers.record(olt, nil)
+	case err != nil && ctx.Err() != nil:
+		// the scan ended, which says nothing about the device
+		breakers.abandon(olt)
+	default:
+		breakers.record(olt, err)
+	}
+	return results, err
 }
 
-func SendCommandAllOlTs(username, password, nokiaCmd, huaweiCmd string) <-chan Result {
+// Commands returns a CommandSet that always runs cmds, whatever the driver.
+func Commands(cmds ...string) CommandSet {
+	return func(Driver) []string { return cmds }
+}
 
-	merged := make(chan Result)
-	var wg sync.WaitGroup
+func SendCommandNokiaOLTs(username, password string, cmds ...string) <-chan Result {
+	return SendCommandNokiaOLTsContext(context.Background(), username, password, cmds...)
+}
 
-	wg.Add(2)
-	go func() {
-		defer wg.Done()
-		for r := range SendCommandNokiaOLTs(username, password, nokiaCmd) {
-			merged <- r
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		for r := range SendCommandHuaweiOLTs(username, password, huaweiCmd) {
-			merged <- r
-		}
-	}()
+// SendCommandNokiaOLTsContext is SendCommandNokiaOLTs bounded by ctx.
+func SendCommandNokiaOLTsContext(ctx context.Context, username, password string, cmds ...string) <-chan Result {
+	return SendOLTsContext(ctx, "nokia", username, password, Commands(cmds...))
+}
 
-	go func() {
-		wg.Wait()
-		close(merged)
-	}()
-	return merged
+func SendCommandHuaweiOLTs(username, password string, cmds ...string) <-chan Result {
+	return SendOLTs("huawei", username, password, Commands(cmds...))
+}
+
+func SendCommandAllOlTs(username, password, nokiaCmd, huaweiCmd string) <-chan Result {
+	return SendOLTs("", username, password, func(d Driver) []string {
+		switch d.Vendor() {
+		case "nokia":
+			return []string{nokiaCmd}
+		case "huawei":
+			return []string{huaweiCmd}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,62 @@
+package shell
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPickCommands(t *testing.T) {
+	rejected := &CommandError{Command: "show b", Kind: ErrSyntax, Line: "Error : invalid token"}
+	cmds := []CommandResult{
+		{Command: "show a", Output: "a", origin: 0},
+		{Command: "show a 1", Output: "a1", origin: 0},
+		{Command: "show b", Output: "Error : invalid token", Failed: true, Err: rejected, origin: 1},
+		{Command: "show c", Failed: true, Err: errors.New("show c: EOF"), origin: 2},
+	}
+
+	tests := []struct {
+		name string
+		idx  []int
+		want []string
+		ok   bool
+	}{
+		{"with follow-ups", []int{0}, []string{"show a", "show a 1"}, true},
+		{"rejected is kept", []int{0, 1}, []string{"show a", "show a 1", "show b"}, true},
+		{"session failed", []int{0, 2}, nil, false},
+		{"never sent", []int{3}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pickCommands(cmds, tt.idx)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d commands, want %d", len(got), len(tt.want))
+			}
+			for i, c := range got {
+				if c.Command != tt.want[i] {
+					t.Errorf("command %d = %q, want %q", i, c.Command, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResultComplete(t *testing.T) {
+	rejected := &CommandError{Command: "show b", Kind: ErrSyntax}
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, true},
+		{rejected, true},
+		{errors.Join(rejected, &CommandError{Command: "show c", Kind: ErrResourceMissing}), true},
+		{errors.New("show c: EOF"), false},
+	}
+	for _, tt := range tests {
+		if got := (Result{Err: tt.err}).Complete(); got != tt.want {
+			t.Errorf("Complete() with %v = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the device while its circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("shell: device unreachable, circuit open")
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerConfig sets how many consecutive failures open a device's circuit
+// and how long to wait before probing an open device again.
+type BreakerConfig struct {
+	Threshold     int
+	ProbeInterval time.Duration
+}
+
+// DeviceStatus is the reachability of one OLT as seen by the breaker.
+type DeviceStatus struct {
+	Host                string       `json:"host"`
+	Device              string       `json:"device"`
+	Site                string       `json:"site"`
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	LastError           string       `json:"last_error,omitempty"`
+	LastFailure         *time.Time   `json:"last_failure,omitempty"`
+	LastSuccess         *time.Time   `json:"last_success,omitempty"`
+	NextProbe           *time.Time   `json:"next_probe,omitempty"`
+}
+
+type breakerSet struct {
+	mu    sync.Mutex
+	cfg   BreakerConfig
+	hosts map[string]*DeviceStatus
+}
+
+var breakers = &breakerSet{
+	cfg:   BreakerConfig{Threshold: 3, ProbeInterval: time.Hour},
+	hosts: map[string]*DeviceStatus{},
+}
+
+// SetBreakerConfig replaces the breaker thresholds. Existing state is kept.
+func SetBreakerConfig(cfg BreakerConfig) {
+	if cfg.Threshold < 1 {
+		cfg.Threshold = 1
+	}
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+	breakers.cfg = cfg
+}
+
+func (b *breakerSet) status(olt OLT) *DeviceStatus {
+	st, ok := b.hosts[olt.Ip]
+	if !ok {
+		st = &DeviceStatus{Host: olt.Ip, State: BreakerClosed}
+		b.hosts[olt.Ip] = st
+	}
+	if olt.Name != "" {
+		st.Device = olt.Name
+	}
+	if olt.Site != "" {
+		st.Site = olt.Site
+	}
+	return st
+}
+
+// allow reports whether a session to olt may be attempted now. An open
+// circuit lets a single probe through once the probe interval has passed.
+func (b *breakerSet) allow(olt OLT) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st := b.status(olt)
+	switch st.State {
+	case BreakerOpen:
+		if st.NextProbe != nil && time.Now().Before(*st.NextProbe) {
+			return ErrCircuitOpen
+		}
+		st.State = BreakerHalfOpen
+		return nil
+	case BreakerHalfOpen:
+		// a probe is already in flight
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// record updates olt's breaker with the outcome of a session. Cancellation
+// by the caller says nothing about the device and is ignored.
+func (b *breakerSet) record(olt OLT, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st := b.status(olt)
+	now := time.Now()
+
+	if err == nil {
+		st.State = BreakerClosed
+		st.ConsecutiveFailures = 0
+		st.LastError = ""
+		st.LastSuccess = &now
+		st.NextProbe = nil
+		return
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrCircuitOpen) {
+		if st.State == BreakerHalfOpen {
+			st.State = BreakerOpen
+		}
+		return
+	}
+
+	st.ConsecutiveFailures++
+	st.LastError = err.Error()
+	st.LastFailure = &now
+	if st.State == BreakerHalfOpen || st.ConsecutiveFailures >= b.cfg.Threshold {
+		next := now.Add(b.cfg.ProbeInterval)
+		st.State = BreakerOpen
+		st.NextProbe = &next
+	}
+}
+
+// abandon ends a probe let through by allow without a verdict.
+func (b *breakerSet) abandon(olt OLT) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if st := b.status(olt); st.State == BreakerHalfOpen {
+		st.State = BreakerOpen
+	}
+}
+
+// DeviceStatuses returns the breaker state of every device contacted since
+// startup, open circuits first.
+func DeviceStatuses() []DeviceStatus {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+
+	out := make([]DeviceStatus, 0, len(breakers.hosts))
+	for _, st := range breakers.hosts {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if (out[i].State == BreakerClosed) != (out[j].State == BreakerClosed) {
+			return out[i].State != BreakerClosed
+		}
+		return out[i].Host < out[j].Host
+	})
+	return out
+}
+
+// ResetBreaker closes host's circuit so the next scan contacts it again.
+func ResetBreaker(host string) bool {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+
+	st, ok := breakers.hosts[host]
+	if !ok {
+		return false
+	}
+	st.State = BreakerClosed
+	st.ConsecutiveFailures = 0
+	st.NextProbe = nil
+	return true
+}
@@ -0,0 +1,202 @@
+package shell
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// Priority orders sessions waiting for the broker. Higher runs first;
+// waiters of equal priority are served in arrival order.
+type Priority int
+
+const (
+	PriorityLow    Priority = -10 // backups and other bulk exports
+	PriorityNormal Priority = 0   // scheduled scans
+	PriorityHigh   Priority = 10  // on-demand user requests
+)
+
+type priorityKey struct{}
+
+// WithPriority tags every session opened under ctx with p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+func priorityFrom(ctx context.Context) Priority {
+	p, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok {
+		return PriorityNormal
+	}
+	return p
+}
+
+// BrokerStats is a snapshot of the session broker.
+type BrokerStats struct {
+	MaxSessions     int            `json:"max_sessions"`
+	MaxPerDevice    int            `json:"max_per_device"`
+	Active          int            `json:"active"`
+	Queued          int            `json:"queued"`
+	ActivePerDevice map[string]int `json:"active_per_device"`
+}
+
+// broker hands out session slots under a global cap and a per-device cap,
+// so jobs whose intervals line up queue for a device instead of logging in
+// to it several times at once.
+type broker struct {
+	mu        sync.Mutex
+	global    int
+	perDevice int
+	active    int
+	perHost   map[string]int
+	queue     waitQueue
+	seq       uint64
+}
+
+var sessions = &broker{global: 33, perDevice: 1, perHost: map[string]int{}}
+
+// SetSessionLimits sets the global and per-device session caps.
+func SetSessionLimits(global, perDevice int) {
+	if global < 1 {
+		global = 1
+	}
+	if perDevice < 1 {
+		perDevice = 1
+	}
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	sessions.global = global
+	sessions.perDevice = perDevice
+	sessions.dispatch()
+}
+
+// SessionStats returns the current broker state.
+func SessionStats() BrokerStats {
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+
+	per := make(map[string]int, len(sessions.perHost))
+	for h, n := range sessions.perHost {
+		per[h] = n
+	}
+	return BrokerStats{
+		MaxSessions:     sessions.global,
+		MaxPerDevice:    sessions.perDevice,
+		Active:          sessions.active,
+		Queued:          sessions.queue.Len(),
+		ActivePerDevice: per,
+	}
+}
+
+type waiter struct {
+	host     string
+	priority Priority
+	seq      uint64
+	ready    chan struct{}
+	granted  bool
+	index    int
+}
+
+// acquire blocks until a session to host may be opened or ctx ends. The
+// returned release must be called once the session is closed.
+func (b *broker) acquire(ctx context.Context, host string) (func(), error) {
+	b.mu.Lock()
+	b.seq++
+	w := &waiter{
+		host:     host,
+		priority: priorityFrom(ctx),
+		seq:      b.seq,
+		ready:    make(chan struct{}),
+	}
+	heap.Push(&b.queue, w)
+	b.dispatch()
+	b.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return b.releaser(host), nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if w.granted {
+			// granted while we were giving up
+			b.release(host)
+		} else {
+			heap.Remove(&b.queue, w.index)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (b *broker) releaser(host string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.release(host)
+		})
+	}
+}
+
+func (b *broker) release(host string) {
+	b.active--
+	if b.perHost[host]--; b.perHost[host] <= 0 {
+		delete(b.perHost, host)
+	}
+	b.dispatch()
+}
+
+// dispatch grants slots to waiters in priority order, skipping waiters
+// whose device is already at its cap. b.mu must be held.
+func (b *broker) dispatch() {
+	var skipped []*waiter
+	for b.active < b.global && b.queue.Len() > 0 {
+		w := heap.Pop(&b.queue).(*waiter)
+		if b.perHost[w.host] >= b.perDevice {
+			skipped = append(skipped, w)
+			continue
+		}
+		b.active++
+		b.perHost[w.host]++
+		w.granted = true
+		close(w.ready)
+	}
+	for _, w := range skipped {
+		heap.Push(&b.queue, w)
+	}
+}
+
+// waitQueue is a container/heap of waiters, highest priority first.
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waitQueue) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waitQueue) Pop() any {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
@@ -0,0 +1,58 @@
+package shell
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBrokerServesHigherPriorityFirst(t *testing.T) {
+	b := &broker{global: 1, perDevice: 1, perHost: map[string]int{}}
+	release, err := b.acquire(context.Background(), "10.0.0.1")
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	order := make(chan Priority, 3)
+	for i, p := range []Priority{PriorityLow, PriorityNormal, PriorityHigh} {
+		go func(p Priority) {
+			done, err := b.acquire(WithPriority(context.Background(), p), "10.0.0.2")
+			if err != nil {
+				t.Errorf("acquire %d: %v", p, err)
+				return
+			}
+			order <- p
+			done()
+		}(p)
+		waitQueued(t, b, i+1)
+	}
+
+	release()
+	for _, want := range []Priority{PriorityHigh, PriorityNormal, PriorityLow} {
+		select {
+		case got := <-order:
+			if got != want {
+				t.Fatalf("served %d, want %d", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("priority %d never served", want)
+		}
+	}
+}
+
+func waitQueued(t *testing.T, b *broker, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		b.mu.Lock()
+		queued := b.queue.Len()
+		b.mu.Unlock()
+		if queued == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d waiters queued, want %d", queued, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
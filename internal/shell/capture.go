@@ -0,0 +1,263 @@
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scrapli/scrapligo/transport"
+)
+
+// CaptureEntry is one line of a capture file. A capture holds a "session"
+// entry with the device prompt followed by a "command" entry per command
+// sent in that session. Raw is what the device sent for the command, echo,
+// control sequences and prompt included (over telnet, the driver's copy of
+// it). Output is the processed form parsers see; replay answers with it and
+// the driver processes it again.
+type CaptureEntry struct {
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	Host      string    `json:"host"`
+	Vendor    string    `json:"vendor,omitempty"`
+	Prompt    string    `json:"prompt,omitempty"`
+	Command   string    `json:"command,omitempty"`
+	Output    string    `json:"output,omitempty"`
+	Raw       string    `json:"raw,omitempty"`
+	ElapsedMs int64     `json:"elapsed_ms,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+var (
+	captureMu  sync.RWMutex
+	recordDir  string
+	replayDir  string
+	captureFMu sync.Mutex // serialises appends to capture files
+)
+
+// SetRecordDir turns on recording: every session appends its commands with
+// their raw and processed outputs to dir/<date>/<host>.jsonl. An empty dir
+// turns it off.
+func SetRecordDir(dir string) {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+	recordDir = dir
+}
+
+// SetReplayDir makes every driver connect to the newest capture of a host
+// under dir instead of the device itself. An empty dir turns it off.
+func SetReplayDir(dir string) {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+	replayDir = dir
+}
+
+func captureDirs() (record, replay string) {
+	captureMu.RLock()
+	defer captureMu.RUnlock()
+	return recordDir, replayDir
+}
+
+func captureName(host string) string {
+	return strings.NewReplacer(":", "_", "/", "-").Replace(host) + ".jsonl"
+}
+
+// recorder appends the entries of one session to a capture file.
+type recorder struct {
+	path string
+}
+
+func newRecorder(dir, host, vendor, prompt string) *recorder {
+	folder := filepath.Join(dir, time.Now().Format("2006-01-02"))
+	if err := os.MkdirAll(folder, 0o755); err != nil {
+		log.Printf("shell: capture %s: %v", host, err)
+		return nil
+	}
+	r := &recorder{path: filepath.Join(folder, captureName(host))}
+	r.write(CaptureEntry{Type: "session", Time: time.Now(), Host: host, Vendor: vendor, Prompt: prompt})
+	return r
+}
+
+func (r *recorder) write(e CaptureEntry) {
+	if r == nil {
+		return
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	captureFMu.Lock()
+	defer captureFMu.Unlock()
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Printf("shell: capture %s: %v", r.path, err)
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(b, '\n'))
+}
+
+// LoadCapture reads every entry of a capture file, e.g. to build extractor
+// fixtures from production output.
+func LoadCapture(path string) ([]CaptureEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []CaptureEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 1024*1024), 256*1024*1024)
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var e CaptureEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
+
+// latestCapture finds the newest capture of host under dir.
+func latestCapture(dir, host string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*", captureName(host)))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("shell: no capture for %s in %s", host, dir)
+	}
+	sort.Strings(matches)
+	return matches[len(matches)-1], nil
+}
+
+// replayTransport plays a capture back as if it were a device: it echoes
+// what the driver types and answers each command with its captured output.
+type replayTransport struct {
+	prompt  string
+	outputs map[string][]string
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending bytes.Buffer
+	line    bytes.Buffer
+	open    bool
+}
+
+// NewReplayTransport loads the capture at path. Commands are answered in
+// the order they were captured; the last output of a command is repeated
+// once it runs out, and unknown commands get an error line.
+func NewReplayTransport(path string) (transport.Implementation, error) {
+	entries, err := LoadCapture(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &replayTransport{outputs: map[string][]string{}}
+	t.cond = sync.NewCond(&t.mu)
+	for _, e := range entries {
+		switch e.Type {
+		case "session":
+			if t.prompt == "" {
+				t.prompt = e.Prompt
+			}
+		case "command":
+			// rejected commands keep their output, so they replay too
+			if e.Error == "" || e.Output != "" {
+				t.outputs[e.Command] = append(t.outputs[e.Command], e.Output)
+			}
+		}
+	}
+	if t.prompt == "" {
+		return nil, fmt.Errorf("shell: capture %s has no prompt", path)
+	}
+	return t, nil
+}
+
+func (t *replayTransport) Open(*transport.Args) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.open = true
+	t.pending.WriteString(t.prompt)
+	return nil
+}
+
+func (t *replayTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.open = false
+	t.cond.Broadcast()
+	return nil
+}
+
+func (t *replayTransport) IsAlive() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.open
+}
+
+func (t *replayTransport) Read(n int) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.open && t.pending.Len() == 0 {
+		t.cond.Wait()
+	}
+	if !t.open {
+		return nil, io.EOF
+	}
+	b := make([]byte, n)
+	n, _ = t.pending.Read(b)
+	return b[:n], nil
+}
+
+func (t *replayTransport) Write(b []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.open {
+		return errors.New("shell: replay transport closed")
+	}
+
+	for _, c := range b {
+		if c != '\n' && c != '\r' {
+			t.line.WriteByte(c)
+			t.pending.WriteByte(c)
+			continue
+		}
+		cmd := strings.TrimSpace(t.line.String())
+		t.line.Reset()
+		t.pending.WriteString("\n")
+		if cmd != "" {
+			t.pending.WriteString(t.answer(cmd))
+			t.pending.WriteString("\n")
+		}
+		t.pending.WriteString(t.prompt)
+	}
+	t.cond.Broadcast()
+	return nil
+}
+
+func (t *replayTransport) answer(cmd string) string {
+	outs := t.outputs[cmd]
+	if len(outs) == 0 {
+		log.Printf("shell: replay: no captured output for %q", cmd)
+		return "replay: no captured output for " + cmd
+	}
+	if len(outs) > 1 {
+		t.outputs[cmd] = outs[1:]
+	}
+	return outs[0]
+}
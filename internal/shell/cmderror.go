@@ -0,0 +1,71 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Kinds of CLI-level failure. A CommandError wraps one of these, so callers
+// can test with errors.Is(err, ErrSyntax).
+var (
+	ErrSyntax          = errors.New("syntax error")
+	ErrNotPermitted    = errors.New("not permitted")
+	ErrResourceMissing = errors.New("resource missing")
+)
+
+// CommandError is returned when the device answered a command with an error
+// message instead of output. The session itself is still usable.
+type CommandError struct {
+	Command string
+	Kind    error
+	// Line is the device's error message.
+	Line string
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("%s: %v: %s", e.Command, e.Kind, e.Line)
+}
+
+func (e *CommandError) Unwrap() error { return e.Kind }
+
+// ErrorPattern maps device error messages matching Re to Kind.
+type ErrorPattern struct {
+	Kind error
+	Re   *regexp.Regexp
+}
+
+// checkOutput returns a CommandError for the first pattern matching out.
+func checkOutput(patterns []ErrorPattern, cmd, out string) error {
+	for _, p := range patterns {
+		if loc := p.Re.FindStringIndex(out); loc != nil {
+			return &CommandError{Command: cmd, Kind: p.Kind, Line: matchedLine(out, loc)}
+		}
+	}
+	return nil
+}
+
+// matchedLine returns the whole line holding the match at loc.
+func matchedLine(s string, loc []int) string {
+	start := strings.LastIndexByte(s[:loc[0]], '\n') + 1
+	end := len(s)
+	if i := strings.IndexByte(s[loc[1]:], '\n'); i >= 0 {
+		end = loc[1] + i
+	}
+	return strings.TrimSpace(s[start:end])
+}
+
+// ErrorKind names the kind of err for reports: "syntax", "not_permitted",
+// "resource_missing", or "session" for anything that is not a CLI error.
+func ErrorKind(err error) string {
+	switch {
+	case errors.Is(err, ErrSyntax):
+		return "syntax"
+	case errors.Is(err, ErrNotPermitted):
+		return "not_permitted"
+	case errors.Is(err, ErrResourceMissing):
+		return "resource_missing"
+	}
+	return "session"
+}
@@ -0,0 +1,143 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/scrapli/scrapligo/driver/generic"
+)
+
+// Driver knows how to reach one OLT platform and which CLI commands produce
+// each kind of data the scheduler collects.
+type Driver interface {
+	// Vendor is the canonical vendor name the driver is registered under.
+	Vendor() string
+	// Connect opens an interactive CLI session on host, giving up when ctx
+	// is done.
+	Connect(ctx context.Context, host, user, pass string) (*generic.Driver, error)
+	// ErrorPatterns recognise the platform's CLI error messages in command
+	// output.
+	ErrorPatterns() []ErrorPattern
+	// InitCommands prepare a fresh session, e.g. by turning off paging and
+	// alarm output. A session profile for the model replaces them.
+	InitCommands() []string
+
+	BackupCommands() []string
+	OpticsCommands() []string
+	StatusCommands() []string
+	// HealthCommands returns the CPU load, uptime and temperature commands,
+	// in that order.
+	HealthCommands() []string
+	PortProtectionCommands() []string
+}
+
+// FollowUpDriver is implemented by drivers whose commands depend on what an
+// earlier command returned, e.g. per-port commands after listing the boards.
+// FollowUp returns the commands to send right after cmd answered with output.
+type FollowUpDriver interface {
+	FollowUp(cmd, output string) []string
+}
+
+// CommandSet selects the commands to run from a driver, for example
+// Driver.OpticsCommands.
+type CommandSet func(Driver) []string
+
+// ErrNoDriver is returned when no registered driver matches an OLT.
+var ErrNoDriver = errors.New("shell: no driver for vendor")
+
+var (
+	driversMu sync.RWMutex
+	// drivers maps a vendor to its default driver ("" key) and any model
+	// specific overrides (lower-cased model key).
+	drivers       = map[string]map[string]Driver{}
+	vendorAliases = map[string]string{}
+)
+
+// RegisterDriver makes d available for its vendor. With no models it becomes
+// the vendor default; otherwise it is only chosen for OLTs whose model
+// contains one of the given names (case-insensitive, longest match wins).
+func RegisterDriver(d Driver, models ...string) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	vendor := strings.ToLower(d.Vendor())
+	if drivers[vendor] == nil {
+		drivers[vendor] = map[string]Driver{}
+	}
+	if len(models) == 0 {
+		drivers[vendor][""] = d
+		return
+	}
+	for _, m := range models {
+		drivers[vendor][strings.ToLower(m)] = d
+	}
+}
+
+// RegisterVendorAlias maps an alternative vendor spelling used in the
+// inventory (e.g. "alcatel") onto a registered vendor.
+func RegisterVendorAlias(alias, vendor string) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	vendorAliases[strings.ToLower(alias)] = strings.ToLower(vendor)
+}
+
+// CanonicalVendor resolves aliases and returns the registered vendor name,
+// or false if nothing is registered for v.
+func CanonicalVendor(v string) (string, bool) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	return canonicalVendor(v)
+}
+
+func canonicalVendor(v string) (string, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if a, ok := vendorAliases[v]; ok {
+		v = a
+	}
+	_, ok := drivers[v]
+	return v, ok
+}
+
+// Vendors lists the registered vendor names.
+func Vendors() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	out := make([]string, 0, len(drivers))
+	for v := range drivers {
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// DriverFor picks the driver for olt from its vendor and model.
+func DriverFor(olt OLT) (Driver, error) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	vendor, ok := canonicalVendor(olt.Vendor)
+	if !ok {
+		return nil, fmt.Errorf("%w %q (%s)", ErrNoDriver, olt.Vendor, olt.Ip)
+	}
+	byModel := drivers[vendor]
+
+	model := strings.ToLower(olt.Model)
+	var best Driver
+	bestLen := 0
+	for key, d := range byModel {
+		if key != "" && len(key) > bestLen && strings.Contains(model, key) {
+			best, bestLen = d, len(key)
+		}
+	}
+	if best != nil {
+		return best, nil
+	}
+	if d, ok := byModel[""]; ok {
+		return d, nil
+	}
+	return nil, fmt.Errorf("%w %q model %q (%s)", ErrNoDriver, olt.Vendor, olt.Model, olt.Ip)
+}
@@ -0,0 +1,100 @@
+package shell
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/repository"
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrHostKeyMismatch is returned when an OLT presents a host key other than
+// the one pinned for it. The session is refused until an admin approves the
+// new key.
+var ErrHostKeyMismatch = errors.New("shell: host key mismatch")
+
+// HostKeyStore keeps the pinned host key of every OLT.
+// repository.HostKeyRepository satisfies it.
+type HostKeyStore interface {
+	GetByHost(host string) (*models.HostKey, error)
+	Save(k *models.HostKey) error
+}
+
+// HostKeyAlert is called when a device presents a key that does not match
+// its pin.
+type HostKeyAlert func(k models.HostKey)
+
+var (
+	hostKeysMu   sync.Mutex
+	hostKeys     HostKeyStore
+	hostKeyAlert HostKeyAlert
+)
+
+// SetHostKeyStore enables trust-on-first-use pinning of SSH host keys.
+// Without a store host keys are not verified.
+func SetHostKeyStore(s HostKeyStore, alert HostKeyAlert) {
+	hostKeysMu.Lock()
+	defer hostKeysMu.Unlock()
+	hostKeys = s
+	hostKeyAlert = alert
+}
+
+// hostKeyCallback pins the first key seen for host and refuses any other
+// key afterwards.
+func hostKeyCallback(host string) ssh.HostKeyCallback {
+	hostKeysMu.Lock()
+	store, alert := hostKeys, hostKeyAlert
+	hostKeysMu.Unlock()
+
+	if store == nil {
+		return ssh.InsecureIgnoreHostKey()
+	}
+
+	return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+		presented := base64.StdEncoding.EncodeToString(key.Marshal())
+		fp := ssh.FingerprintSHA256(key)
+
+		pinned, err := store.GetByHost(host)
+		if errors.Is(err, repository.ErrHostKeyNotFound) {
+			log.Printf("shell: pinning %s host key for %s (%s)", key.Type(), host, fp)
+			return store.Save(&models.HostKey{
+				Host:        host,
+				KeyType:     key.Type(),
+				PublicKey:   presented,
+				Fingerprint: fp,
+				Status:      models.HostKeyTrusted,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("shell: host key lookup for %s: %w", host, err)
+		}
+
+		if pinned.KeyType == key.Type() && pinned.PublicKey == presented {
+			return nil
+		}
+
+		// Only alert once per new key; repeated scans against the same
+		// rogue key stay quiet until an admin acts.
+		if pinned.Status != models.HostKeyMismatch || pinned.PendingPublicKey != presented {
+			now := time.Now()
+			pinned.Status = models.HostKeyMismatch
+			pinned.PendingKeyType = key.Type()
+			pinned.PendingPublicKey = presented
+			pinned.PendingFingerprint = fp
+			pinned.MismatchAt = &now
+			if err := store.Save(pinned); err != nil {
+				log.Printf("shell: save host key mismatch for %s: %v", host, err)
+			}
+			if alert != nil {
+				alert(*pinned)
+			}
+		}
+		return fmt.Errorf("%w for %s: pinned %s, presented %s", ErrHostKeyMismatch, host, pinned.Fingerprint, fp)
+	}
+}
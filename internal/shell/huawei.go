@@ -0,0 +1,125 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Flafl/DevOpsCore/internal/extractor"
+	"github.com/scrapli/scrapligo/driver/generic"
+	"github.com/scrapli/scrapligo/driver/options"
+)
+
+func init() {
+	RegisterDriver(huaweiMA5800{})
+}
+
+// huaweiMA5800 drives Huawei MA5800/MA5600T OLTs.
+type huaweiMA5800 struct{}
+
+var huaweiPrompt = regexp.MustCompile(`(?m)[<>]\S+[<>]\s*$`)
+
+// MA5800 telnet asks ">>User name:" then ">>User password:".
+var huaweiLogin = loginPrompts{
+	Username: regexp.MustCompile(`(?im)user\s*name:\s*$`),
+	Password: regexp.MustCompile(`(?im)password:\s*$`),
+}
+
+// MA5800 prints parser errors as "% ..." and rejected operations as
+// "Failure: ...".
+var huaweiErrors = []ErrorPattern{
+	{ErrResourceMissing, regexp.MustCompile(`(?mi)^\s*(?:failure|error)\s*:.*(?:does not exist|not exist|not found)`)},
+	{ErrNotPermitted, regexp.MustCompile(`(?mi)^\s*(?:%|failure\s*:|error\s*:).*(?:permission denied|no authority|insufficient privilege|not authori[sz]ed)`)},
+	{ErrSyntax, regexp.MustCompile(`(?mi)^\s*%\s*(?:unknown command|incomplete command|too many parameters|parameter error|ambiguous command|wrong parameter)`)},
+}
+
+func (huaweiMA5800) Vendor() string { return "huawei" }
+
+func (huaweiMA5800) ErrorPatterns() []ErrorPattern { return huaweiErrors }
+
+func (huaweiMA5800) InitCommands() []string {
+	return []string{"screen-length 0 temporary"}
+}
+
+func (huaweiMA5800) Connect(ctx context.Context, host, user, pass string) (*generic.Driver, error) {
+	host, dial, err := dialOptions(ctx, host, huaweiLogin)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := generic.NewDriver(host, append(dial,
+		options.WithAuthUsername(user),
+		options.WithAuthPassword(pass),
+		options.WithPromptPattern(huaweiPrompt),
+		options.WithTimeoutOps(CommandTimeout("")),
+		options.WithTermWidth(511),
+	)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := openContext(ctx, driver); err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+func (huaweiMA5800) BackupCommands() []string {
+	return []string{"display current-configuration"}
+}
+
+// OpticsCommands lists the boards; FollowUp then asks each GPON port.
+func (huaweiMA5800) OpticsCommands() []string {
+	return []string{"display board 0"}
+}
+
+// huaweiGponPorts is the port count of each GPON service board family.
+var huaweiGponPorts = map[string]int{
+	"GPBD": 8,
+	"GPBH": 8,
+	"GPFD": 16,
+	"GPHF": 16,
+	"GPLF": 16,
+	"GPSF": 16,
+	"GPUF": 16,
+}
+
+// FollowUp asks every port of the normal GPON boards. A port without ONTs
+// answers "Failure: The ONT does not exist", which is read as empty output.
+func (huaweiMA5800) FollowUp(cmd, output string) []string {
+	if cmd != "display board 0" {
+		return nil
+	}
+	var out []string
+	for _, b := range extractor.ExtractHuaweiBoards(output) {
+		if !strings.Contains(strings.ToLower(b.Status), "normal") {
+			continue
+		}
+		for family, ports := range huaweiGponPorts {
+			if !strings.Contains(b.Name, family) {
+				continue
+			}
+			for p := 0; p < ports; p++ {
+				out = append(out, fmt.Sprintf("display ont optical-info 0/%d/%d all", b.Slot, p))
+			}
+			break
+		}
+	}
+	return out
+}
+
+func (huaweiMA5800) StatusCommands() []string {
+	return []string{"display ont info 0 all"}
+}
+
+func (huaweiMA5800) HealthCommands() []string {
+	return []string{
+		"display cpu 0/0",
+		"display sysuptime",
+		"display temperature 0/0",
+	}
+}
+
+func (huaweiMA5800) PortProtectionCommands() []string {
+	return []string{"display protect-group"}
+}
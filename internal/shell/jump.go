@@ -0,0 +1,73 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+)
+
+// JumpHop is one SSH bastion on the way to an OLT.
+type JumpHop struct {
+	Name     string
+	Addr     string
+	Username string
+	Password string
+}
+
+// HopError reports which hop of a jump chain failed. Hop is 1-based; the
+// OLT itself is reported as the hop after the last bastion.
+type HopError struct {
+	Hop  int
+	Name string
+	Addr string
+	Err  error
+}
+
+func (e *HopError) Error() string {
+	return fmt.Sprintf("jump hop %d (%s %s): %v", e.Hop, e.Name, e.Addr, e.Err)
+}
+
+func (e *HopError) Unwrap() error { return e.Err }
+
+// JumpResolver returns the chain of jump hosts for an OLT, nil for a direct
+// connection. credentials.Store satisfies it.
+type JumpResolver interface {
+	Route(host, site string) ([]JumpHop, error)
+}
+
+var jumpResolver JumpResolver
+
+// SetJumpResolver installs the jump-host lookup used for every session.
+func SetJumpResolver(r JumpResolver) {
+	jumpResolver = r
+}
+
+// dialPlan is how a driver should reach one OLT. It travels to
+// Driver.Connect through the context so the driver interface stays the same
+// for every transport.
+type dialPlan struct {
+	Transport string
+	Hops      []JumpHop
+	// Model picks the session profile once connected.
+	Model string
+}
+
+type dialPlanKey struct{}
+
+// withDialPlan resolves the transport and jump chain of olt and carries them
+// to the driver through ctx.
+func withDialPlan(ctx context.Context, olt OLT) (context.Context, error) {
+	plan := dialPlan{Transport: olt.Transport, Model: olt.Model}
+	if jumpResolver != nil {
+		hops, err := jumpResolver.Route(olt.Ip, olt.Site)
+		if err != nil {
+			return ctx, fmt.Errorf("shell: jump route for %s: %w", olt.Ip, err)
+		}
+		plan.Hops = hops
+	}
+	return context.WithValue(ctx, dialPlanKey{}, plan), nil
+}
+
+func dialPlanFrom(ctx context.Context) dialPlan {
+	plan, _ := ctx.Value(dialPlanKey{}).(dialPlan)
+	return plan
+}
@@ -0,0 +1,94 @@
+package shell
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/scrapli/scrapligo/driver/generic"
+	"github.com/scrapli/scrapligo/driver/options"
+)
+
+func init() {
+	RegisterDriver(nokiaISAM{})
+	RegisterVendorAlias("alcatel", "nokia")
+	RegisterVendorAlias("alcatel-lucent", "nokia")
+	RegisterVendorAlias("alu", "nokia")
+}
+
+// nokiaISAM drives Nokia/Alcatel-Lucent ISAM shelves (FX-4/8/16, 7360, 7342).
+type nokiaISAM struct{}
+
+var nokiaPrompt = regexp.MustCompile(`(?m)(>#)\s*$`)
+
+// ISAM telnet asks "login:" then "password:".
+var nokiaLogin = loginPrompts{
+	Username: regexp.MustCompile(`(?im)^\s*login:\s*$`),
+	Password: regexp.MustCompile(`(?im)^\s*password:\s*$`),
+}
+
+// ISAM prints errors as "Error : <reason>" under a caret marking the token.
+var nokiaErrors = []ErrorPattern{
+	{ErrResourceMissing, regexp.MustCompile(`(?mi)^\s*error\s*:\s*.*(?:does not exist|not found|not equipped|not planned)`)},
+	{ErrNotPermitted, regexp.MustCompile(`(?mi)^\s*error\s*:\s*.*(?:insufficient privilege|permission denied|access denied|not authori[sz]ed)`)},
+	{ErrSyntax, regexp.MustCompile(`(?mi)^\s*error\s*:\s*.*(?:invalid token|command is not complete|invalid (?:value|parameter)|ambiguous|too many)`)},
+}
+
+func (nokiaISAM) Vendor() string { return "nokia" }
+
+func (nokiaISAM) ErrorPatterns() []ErrorPattern { return nokiaErrors }
+
+// InitCommands keep asynchronous alarm lines out of command output, and batch
+// mode turns off paging and the progress spinner.
+func (nokiaISAM) InitCommands() []string {
+	return []string{
+		"environment inhibit-alarms",
+		"environment mode batch",
+	}
+}
+
+func (nokiaISAM) Connect(ctx context.Context, host, user, pass string) (*generic.Driver, error) {
+	host, dial, err := dialOptions(ctx, host, nokiaLogin)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := generic.NewDriver(host, append(dial,
+		options.WithAuthUsername(user),
+		options.WithAuthPassword(pass),
+		// options.WithChannelLog(os.Stdout),
+		options.WithPromptPattern(nokiaPrompt),
+		options.WithTimeoutOps(CommandTimeout("")),
+		options.WithTermWidth(511),
+	)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := openContext(ctx, driver); err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+func (nokiaISAM) BackupCommands() []string {
+	return []string{"info configure flat"}
+}
+
+func (nokiaISAM) OpticsCommands() []string {
+	return []string{"show equipment ont optics"}
+}
+
+func (nokiaISAM) StatusCommands() []string {
+	return []string{"show equipment ont status pon"}
+}
+
+func (nokiaISAM) HealthCommands() []string {
+	return []string{
+		"show system cpu-load detail",
+		"show core1-uptime",
+		"show equipment temperature",
+	}
+}
+
+func (nokiaISAM) PortProtectionCommands() []string {
+	return []string{"show port-protection"}
+}
@@ -0,0 +1,74 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SessionProfile lists the commands sent right after login to OLTs of Vendor
+// whose model contains Model (case-insensitive, longest match wins). An
+// empty Model applies to every model of the vendor.
+type SessionProfile struct {
+	Vendor   string   `json:"vendor"`
+	Model    string   `json:"model,omitempty"`
+	Commands []string `json:"commands"`
+}
+
+var (
+	profilesMu sync.RWMutex
+	profiles   []SessionProfile
+)
+
+// SetSessionProfiles replaces the session profiles. Vendors and models
+// without a profile use their driver's InitCommands.
+func SetSessionProfiles(p []SessionProfile) {
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	profiles = p
+}
+
+// LoadSessionProfiles reads a JSON array of profiles from path.
+func LoadSessionProfiles(path string) ([]SessionProfile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []SessionProfile
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i, p := range out {
+		vendor, ok := CanonicalVendor(p.Vendor)
+		if !ok {
+			return nil, fmt.Errorf("%s: profile %d: %w %q", path, i, ErrNoDriver, p.Vendor)
+		}
+		out[i].Vendor = vendor
+	}
+	return out, nil
+}
+
+// initCommands returns the commands that prepare a session on a model of d.
+func initCommands(d Driver, model string) []string {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+
+	model = strings.ToLower(model)
+	var best *SessionProfile
+	bestLen := -1
+	for i, p := range profiles {
+		if p.Vendor != d.Vendor() {
+			continue
+		}
+		key := strings.ToLower(p.Model)
+		if len(key) > bestLen && strings.Contains(model, key) {
+			best, bestLen = &profiles[i], len(key)
+		}
+	}
+	if best != nil {
+		return best.Commands
+	}
+	return d.InitCommands()
+}
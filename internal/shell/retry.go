@@ -0,0 +1,95 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how often a failed session is retried and how long to
+// wait between attempts. Delays grow exponentially from BaseDelay up to
+// MaxDelay, with up to Jitter (0..1) of each delay randomised.
+type RetryPolicy struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Jitter    float64
+}
+
+var (
+	retryMu     sync.RWMutex
+	retryPolicy = RetryPolicy{
+		Attempts:  3,
+		BaseDelay: 5 * time.Second,
+		MaxDelay:  time.Minute,
+		Jitter:    0.2,
+	}
+)
+
+// SetRetryPolicy replaces the policy used by the SendCommand*OLTs helpers.
+func SetRetryPolicy(p RetryPolicy) {
+	if p.Attempts < 1 {
+		p.Attempts = 1
+	}
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	retryPolicy = p
+}
+
+func currentRetryPolicy() RetryPolicy {
+	retryMu.RLock()
+	defer retryMu.RUnlock()
+	return retryPolicy
+}
+
+// backoff returns the wait before retry number attempt (1-based).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 && d > 0 {
+		spread := float64(d) * p.Jitter
+		d += time.Duration(spread*rand.Float64()*2 - spread)
+	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
+
+// retryable reports whether err is worth another attempt. Cancellation and
+// deadlines of the caller are final, and so is a device rejecting a command.
+func retryable(err error) bool {
+	var ce *CommandError
+	return err != nil &&
+		!errors.As(err, &ce) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) &&
+		!errors.Is(err, ErrCircuitOpen) &&
+		!errors.Is(err, ErrHostKeyMismatch)
+}
+
+// withRetry runs fn until it succeeds, the policy is exhausted or ctx ends.
+func withRetry(ctx context.Context, p RetryPolicy, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if !retryable(err) || attempt >= p.Attempts {
+			return err
+		}
+
+		t := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scrapli/scrapligo/driver/generic"
+	"github.com/scrapli/scrapligo/driver/opoptions"
+)
+
+var (
+	timeoutsMu            sync.RWMutex
+	defaultCommandTimeout = 2 * time.Minute
+	// commandTimeouts holds per-command overrides keyed by command prefix.
+	// Full table dumps and config exports can take many minutes on large
+	// shelves; everything else should answer quickly.
+	commandTimeouts = map[string]time.Duration{
+		"info configure":                30 * time.Minute,
+		"display current-configuration": 30 * time.Minute,
+		"show running-config":           30 * time.Minute,
+		"show equipment ont optics":     15 * time.Minute,
+		"show equipment ont status":     15 * time.Minute,
+		"display ont optical-info":      15 * time.Minute,
+	}
+)
+
+// SetDefaultCommandTimeout sets the timeout for commands without an override.
+func SetDefaultCommandTimeout(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	timeoutsMu.Lock()
+	defer timeoutsMu.Unlock()
+	defaultCommandTimeout = d
+}
+
+// SetCommandTimeout overrides the timeout of every command starting with
+// prefix.
+func SetCommandTimeout(prefix string, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	timeoutsMu.Lock()
+	defer timeoutsMu.Unlock()
+	commandTimeouts[prefix] = d
+}
+
+// CommandTimeout returns the timeout for cmd: the longest matching prefix
+// override, or the default.
+func CommandTimeout(cmd string) time.Duration {
+	timeoutsMu.RLock()
+	defer timeoutsMu.RUnlock()
+
+	best, bestLen := defaultCommandTimeout, 0
+	for prefix, d := range commandTimeouts {
+		if len(prefix) > bestLen && strings.HasPrefix(cmd, prefix) {
+			best, bestLen = d, len(prefix)
+		}
+	}
+	return best
+}
+
+// Session is an open CLI session on one OLT. Cancelling the context it was
+// opened with tears the transport down, which unblocks any command that is
+// still waiting for the device.
+type Session struct {
+	driver   *generic.Driver
+	stop     func() bool
+	host     string
+	rec      *recorder
+	patterns []ErrorPattern
+}
+
+// Open connects to host with d, ties the session to ctx and sends the
+// session profile. A profile command the device rejects is logged and
+// skipped.
+func Open(ctx context.Context, d Driver, host, user, pass string) (*Session, error) {
+	driver, err := d.Connect(ctx, host, user, pass)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{driver: driver, host: host, patterns: d.ErrorPatterns()}
+	s.stop = context.AfterFunc(ctx, func() {
+		_ = driver.Transport.Close(true)
+	})
+
+	if dir, _ := captureDirs(); dir != "" {
+		prompt, err := driver.GetPrompt()
+		if err != nil {
+			log.Printf("shell: capture %s: prompt: %v", host, err)
+		}
+		s.rec = newRecorder(dir, host, d.Vendor(), prompt)
+	}
+
+	for _, cmd := range initCommands(d, dialPlanFrom(ctx).Model) {
+		_, err := s.Send(ctx, cmd)
+		var ce *CommandError
+		if errors.As(err, &ce) {
+			log.Printf("shell: %s: session profile: %v", host, err)
+			continue
+		}
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Send runs cmd and returns its output. The command is bounded by its
+// CommandTimeout and by ctx, whichever ends first. When the device rejects
+// cmd the output is returned with a *CommandError and the session stays
+// usable.
+func (s *Session) Send(ctx context.Context, cmd string) (string, error) {
+	out, _, err := s.send(ctx, cmd)
+	return out, err
+}
+
+// rawTap is a transport that can hand over the bytes it read for one
+// command, before the driver strips anything.
+type rawTap interface {
+	startRaw()
+	takeRaw() string
+}
+
+// send is Send that also returns the raw output: the bytes read from the
+// transport when it is a rawTap, otherwise the driver's RawResult.
+func (s *Session) send(ctx context.Context, cmd string) (out, raw string, err error) {
+	if err := ctx.Err(); err != nil {
+		return "", "", err
+	}
+
+	timeout := CommandTimeout(cmd)
+	if dl, ok := ctx.Deadline(); ok {
+		if left := time.Until(dl); left < timeout {
+			timeout = left
+		}
+	}
+
+	// A command that overruns leaves the channel mid-output, so the session
+	// is torn down rather than reused. stop is deferred after cancel so it
+	// runs first and a normal return never triggers the close.
+	cctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	stop := context.AfterFunc(cctx, func() {
+		_ = s.driver.Transport.Close(true)
+	})
+	defer stop()
+
+	tap, _ := s.driver.Transport.Impl.(rawTap)
+	if tap != nil {
+		tap.startRaw()
+	}
+	start := time.Now()
+	r, err := s.driver.SendCommand(cmd, opoptions.WithTimeoutOps(timeout))
+	if tap != nil {
+		raw = tap.takeRaw()
+	} else if r != nil {
+		raw = string(r.RawResult)
+	}
+	if err != nil {
+		if ctxErr := cctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		s.record(cmd, "", raw, start, err)
+		return "", raw, fmt.Errorf("%s: %w", cmd, err)
+	}
+	err = checkOutput(s.patterns, cmd, r.Result)
+	s.record(cmd, r.Result, raw, start, err)
+	return r.Result, raw, err
+}
+
+func (s *Session) record(cmd, out, raw string, start time.Time, err error) {
+	if s.rec == nil {
+		return
+	}
+	e := CaptureEntry{
+		Type:      "command",
+		Time:      start,
+		Host:      s.host,
+		Command:   cmd,
+		Output:    out,
+		Raw:       raw,
+		ElapsedMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	s.rec.write(e)
+}
+
+// Close ends the session.
+func (s *Session) Close() error {
+	s.stop()
+	return s.driver.Close()
+}
+
+// openContext opens driver but gives up as soon as ctx is done. A connection
+// that completes after the caller gave up is closed in the background.
+func openContext(ctx context.Context, driver *generic.Driver) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- driver.Open() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		_ = driver.Transport.Close(true)
+		go func() {
+			if err := <-done; err == nil {
+				_ = driver.Close()
+			}
+		}()
+		return ctx.Err()
+	}
+}
+
+// sendCommandOLT runs cmds on olt under the retry policy, returning the
+// output of a single command or the joined output of several.
+func sendCommandOLT(ctx context.Context, d Driver, olt OLT, user, pass string, cmds ...string) (string, error) {
+	results, err := sendWithRetry(ctx, d, olt, user, pass, cmds...)
+	if err != nil {
+		return "", err
+	}
+	return JoinedOutput(results), nil
+}
+
+// runCommands waits for a broker slot, opens a session with d and runs cmds
+// in order, each followed by its follow-ups when d is a FollowUpDriver.
+// Commands the device rejects are marked Failed and the rest still run;
+// their errors are returned joined. Any other failure ends the session and
+// is returned as the last result.
+func runCommands(ctx context.Context, d Driver, host, user, pass string, cmds ...string) ([]CommandResult, error) {
+	release, err := sessions.acquire(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	s, err := Open(ctx, d, host, user, pass)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	follow, _ := d.(FollowUpDriver)
+	return sendQueue(ctx, s.send, follow, cmds)
+}
+
+// sendQueue sends cmds with send, queueing the follow-ups of each command
+// right behind it. A follow-up asking about something the device does not
+// have, e.g. an unused port on a board, answers with an empty output rather
+// than a failure.
+func sendQueue(ctx context.Context, send func(context.Context, string) (string, string, error), follow FollowUpDriver, cmds []string) ([]CommandResult, error) {
+	type pending struct {
+		cmd      string
+		origin   int
+		followUp bool
+	}
+	queue := make([]pending, len(cmds))
+	for i, cmd := range cmds {
+		queue[i] = pending{cmd: cmd, origin: i}
+	}
+
+	results := make([]CommandResult, 0, len(cmds))
+	var rejected []error
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		start := time.Now()
+		out, raw, err := send(ctx, p.cmd)
+		if p.followUp && errors.Is(err, ErrResourceMissing) {
+			out, err = "", nil
+		}
+		results = append(results, CommandResult{
+			Command: p.cmd,
+			Output:  out,
+			Raw:     raw,
+			Elapsed: time.Since(start),
+			Failed:  err != nil,
+			Err:     err,
+			origin:  p.origin,
+		})
+		var ce *CommandError
+		if errors.As(err, &ce) {
+			rejected = append(rejected, err)
+			continue
+		}
+		if err != nil {
+			return results, err
+		}
+		if follow != nil {
+			var next []pending
+			for _, cmd := range follow.FollowUp(p.cmd, out) {
+				next = append(next, pending{cmd, p.origin, true})
+			}
+			queue = append(next, queue...)
+		}
+	}
+	return results, errors.Join(rejected...)
+}
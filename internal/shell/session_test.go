@@ -0,0 +1,95 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const hwBoards = `  -------------------------------------------------------------------------
+  SlotID  BoardName  Status          SubType0 SubType1    Online/Offline
+  -------------------------------------------------------------------------
+  0
+  1       H901GPBD   Normal
+  2
+  9       H902MPLA   Active_normal   CPCF
+  -------------------------------------------------------------------------
+`
+
+const hwOptics = `  -----------------------------------------------------------------------------
+  ONT  Rx power  Tx power  OLT Rx ONT  Temperature  Voltage  Current
+  ID   (dBm)     (dBm)     power(dBm)  (C)          (V)      (mA)
+  -----------------------------------------------------------------------------
+  0    -19.52    2.31      -21.43      45           3.280    12
+  -----------------------------------------------------------------------------
+`
+
+// hwSender answers like an MA5800 where every GPON port of slot 1 but
+// empty has an ONT.
+func hwSender(empty string) func(context.Context, string) (string, string, error) {
+	return func(_ context.Context, cmd string) (string, string, error) {
+		out := hwOptics
+		switch cmd {
+		case "display board 0":
+			out = hwBoards
+		case empty, "display ont optical-info 0/2/0 all":
+			out = "  Failure: The ONT does not exist"
+		}
+		raw := cmd + "\r\n" + strings.ReplaceAll(out, "\n", "\r\n") + "\r\n<MA5800>"
+		return out, raw, checkOutput(huaweiErrors, cmd, out)
+	}
+}
+
+func TestSendQueueFollowUpMissingIsEmpty(t *testing.T) {
+	empty := "display ont optical-info 0/1/3 all"
+	results, err := sendQueue(context.Background(), hwSender(empty), huaweiMA5800{}, []string{"display board 0"})
+	if err != nil {
+		t.Fatalf("sendQueue: %v", err)
+	}
+	if len(results) != 9 {
+		t.Fatalf("got %d results, want the board list and 8 ports", len(results))
+	}
+	for i, r := range results {
+		if r.Failed || r.Err != nil {
+			t.Errorf("%s: failed: %v", r.Command, r.Err)
+		}
+		if r.origin != 0 {
+			t.Errorf("%s: origin %d, want 0", r.Command, r.origin)
+		}
+		if !strings.HasPrefix(r.Raw, r.Command+"\r\n") {
+			t.Errorf("%s: raw output %q not kept", r.Command, r.Raw)
+		}
+		if i == 0 {
+			continue
+		}
+		want := fmt.Sprintf("display ont optical-info 0/1/%d all", i-1)
+		if r.Command != want {
+			t.Errorf("result %d is %q, want %q", i, r.Command, want)
+		}
+		if r.Command == empty && r.Output != "" {
+			t.Errorf("%s: output %q, want empty", r.Command, r.Output)
+		}
+		if r.Command != empty && r.Output == "" {
+			t.Errorf("%s: empty output", r.Command)
+		}
+	}
+}
+
+func TestSendQueueRequestedMissingFails(t *testing.T) {
+	cmd := "display ont optical-info 0/2/0 all"
+	results, err := sendQueue(context.Background(), hwSender(""), huaweiMA5800{}, []string{cmd, "display sysuptime"})
+	if !errors.Is(err, ErrResourceMissing) {
+		t.Fatalf("err = %v, want %v", err, ErrResourceMissing)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if !results[0].Failed {
+		t.Errorf("%s: not failed", cmd)
+	}
+	if results[1].Failed {
+		t.Errorf("%s: failed after a rejected command", results[1].Command)
+	}
+}
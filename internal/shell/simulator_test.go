@@ -0,0 +1,150 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/extractor"
+	"github.com/Flafl/DevOpsCore/internal/models"
+	"github.com/Flafl/DevOpsCore/internal/simulator"
+)
+
+type staticInventory []models.Olt
+
+func (s staticInventory) GetEnabled() ([]models.Olt, error) { return s, nil }
+
+// startSimulator serves a simulated ISAM shelf and lists it as the only
+// OLT of the inventory.
+func startSimulator(t *testing.T) string {
+	t.Helper()
+	sim, err := simulator.New(simulator.Config{Hostname: "sim", User: "isadmin", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go sim.Serve(ln)
+	t.Cleanup(func() { sim.Close() })
+
+	host := ln.Addr().String()
+	SetInventory(staticInventory{{Name: "sim", Site: "lab", Host: host, Vendor: "nokia"}})
+	t.Cleanup(func() { SetInventory(nil) })
+	return host
+}
+
+func TestSendPlanAgainstSimulator(t *testing.T) {
+	startSimulator(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	plan := []PlanStep{
+		{Name: "optics", Set: Driver.OpticsCommands},
+		{Name: "bogus", Set: Commands("show bogus")},
+		{Name: "ports", Set: Driver.PortProtectionCommands},
+	}
+	var results []Result
+	for r := range SendPlanContext(ctx, "", "isadmin", "secret", plan) {
+		results = append(results, r)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	r := results[0]
+
+	var ce *CommandError
+	if !errors.As(r.Err, &ce) || !errors.Is(r.Err, ErrSyntax) {
+		t.Fatalf("err = %v, want the rejected bogus command", r.Err)
+	}
+	if !r.Complete() || r.Data == "" {
+		t.Errorf("result not complete after a rejected command")
+	}
+
+	fixture, err := os.ReadFile("../simulator/fixtures/show-equipment-ont-optics.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := extractor.ExtractAllOntPower(string(fixture))
+	got := extractor.ExtractAllOntPower(JoinedOutput(r.Steps["optics"]))
+	if len(want) == 0 || len(got) != len(want) {
+		t.Fatalf("parsed %d ONTs from the session, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].OntIdx != want[i].OntIdx || got[i].OltRx != want[i].OltRx {
+			t.Errorf("ONT %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if len(extractor.ExtractPortProtection(JoinedOutput(r.Steps["ports"]))) == 0 {
+		t.Error("no port protection rows from the session")
+	}
+	if bogus := r.Steps["bogus"]; len(bogus) != 1 || !bogus[0].Failed {
+		t.Errorf("bogus step = %+v, want one failed command", bogus)
+	}
+	for _, c := range r.Commands {
+		if !strings.Contains(c.Raw, "typ:sim>#") || strings.Contains(c.Output, "typ:sim>#") {
+			t.Errorf("%s: raw %q, output %q", c.Command, c.Raw, c.Output)
+		}
+	}
+}
+
+func TestNkSendCommandOLTAgainstSimulator(t *testing.T) {
+	host := startSimulator(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	out, err := NkSendCommandOLTContext(ctx, host, "isadmin", "secret", "show equipment ont status pon")
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(extractor.ExtractAllDesc(out)) == 0 {
+		t.Errorf("no ONT descriptions in %q", out)
+	}
+	// the output is the processed form: no echo, prompt or carriage returns
+	if strings.HasPrefix(out, "show equipment") || strings.Contains(out, "typ:sim>#") || strings.Contains(out, "\r") {
+		t.Errorf("output not processed: %q", out)
+	}
+}
+
+func TestRecordKeepsRawOutput(t *testing.T) {
+	host := startSimulator(t)
+	dir := t.TempDir()
+	SetRecordDir(dir)
+	t.Cleanup(func() { SetRecordDir("") })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := NkSendCommandOLTContext(ctx, host, "isadmin", "secret", "show equipment ont status pon"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	path, err := latestCapture(dir, host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := LoadCapture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, e := range entries {
+		if e.Type != "command" || e.Command != "show equipment ont status pon" {
+			continue
+		}
+		found = true
+		if !strings.Contains(e.Raw, "\r\n") || !strings.Contains(e.Raw, "typ:sim>#") || !strings.Contains(e.Raw, e.Command) {
+			t.Errorf("raw output lost the device framing: %q", e.Raw)
+		}
+		if e.Output == "" || strings.Contains(e.Output, "\r") || strings.Contains(e.Output, "typ:sim>#") {
+			t.Errorf("processed output = %q", e.Output)
+		}
+	}
+	if !found {
+		t.Fatalf("command not recorded in %s", path)
+	}
+}
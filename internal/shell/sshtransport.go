@@ -0,0 +1,234 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/scrapli/scrapligo/transport"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshTransport is a crypto/ssh transport for scrapligo that verifies host
+// keys against the pinned keys instead of ignoring them, and can tunnel
+// through a chain of jump hosts.
+type sshTransport struct {
+	hops []JumpHop
+
+	mu     sync.Mutex
+	closed bool
+	// clients holds the jump clients in dialling order followed by the
+	// OLT client, so Close can tear the chain down from the far end.
+	clients []*ssh.Client
+	session *ssh.Session
+	writer  io.WriteCloser
+	reader  io.Reader
+
+	// tap, when set, collects every byte read for the capture file.
+	tapMu sync.Mutex
+	tap   *bytes.Buffer
+}
+
+func newSSHTransport(hops []JumpHop) *sshTransport {
+	return &sshTransport{hops: hops}
+}
+
+func passwordAuth(pass string) []ssh.AuthMethod {
+	return []ssh.AuthMethod{
+		ssh.Password(pass),
+		ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+			answers := make([]string, len(questions))
+			for i := range answers {
+				answers[i] = pass
+			}
+			return answers, nil
+		}),
+	}
+}
+
+// withPort adds port to addr unless it already carries one, e.g. a
+// simulator on 127.0.0.1:2201.
+func withPort(addr string, port int) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	return net.JoinHostPort(addr, strconv.Itoa(port))
+}
+
+// dial opens an SSH client to addr, directly or over the previous client in
+// the chain.
+func (t *sshTransport) dial(addr string, cfg *ssh.ClientConfig, timeout time.Duration) (*ssh.Client, error) {
+	t.mu.Lock()
+	var via *ssh.Client
+	if n := len(t.clients); n > 0 {
+		via = t.clients[n-1]
+	}
+	t.mu.Unlock()
+
+	var client *ssh.Client
+	if via == nil {
+		c, err := ssh.Dial("tcp", addr, cfg)
+		if err != nil {
+			return nil, err
+		}
+		client = c
+	} else {
+		conn, err := via.Dial("tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		if timeout > 0 {
+			_ = conn.SetDeadline(time.Now().Add(timeout))
+		}
+		c, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		_ = conn.SetDeadline(time.Time{})
+		client = ssh.NewClient(c, chans, reqs)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		// Close ran while we were dialling
+		_ = client.Close()
+		return nil, net.ErrClosed
+	}
+	t.clients = append(t.clients, client)
+	return client, nil
+}
+
+// Open dials the chain and starts the shell. On failure every client
+// dialled so far is closed again.
+func (t *sshTransport) Open(a *transport.Args) error {
+	if err := t.open(a); err != nil {
+		_ = t.Close()
+		return err
+	}
+	return nil
+}
+
+func (t *sshTransport) open(a *transport.Args) error {
+	for i, hop := range t.hops {
+		addr := withPort(hop.Addr, 22)
+		_, err := t.dial(addr, &ssh.ClientConfig{
+			User:            hop.Username,
+			Auth:            passwordAuth(hop.Password),
+			Timeout:         a.TimeoutSocket,
+			HostKeyCallback: hostKeyCallback(hop.Addr),
+		}, a.TimeoutSocket)
+		if err != nil {
+			return &HopError{Hop: i + 1, Name: hop.Name, Addr: addr, Err: err}
+		}
+	}
+
+	addr := withPort(a.Host, a.Port)
+	client, err := t.dial(addr, &ssh.ClientConfig{
+		User:            a.User,
+		Auth:            passwordAuth(a.Password),
+		Timeout:         a.TimeoutSocket,
+		HostKeyCallback: hostKeyCallback(a.Host),
+	}, a.TimeoutSocket)
+	if err != nil {
+		if len(t.hops) > 0 {
+			return &HopError{Hop: len(t.hops) + 1, Name: "olt", Addr: addr, Err: err}
+		}
+		return fmt.Errorf("ssh %s: %w", addr, err)
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.session = session
+	t.mu.Unlock()
+
+	if t.writer, err = session.StdinPipe(); err != nil {
+		return err
+	}
+	if t.reader, err = session.StdoutPipe(); err != nil {
+		return err
+	}
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 115200,
+		ssh.TTY_OP_OSPEED: 115200,
+	}
+	if err := session.RequestPty("xterm", a.TermHeight, a.TermWidth, modes); err != nil {
+		return err
+	}
+	return session.Shell()
+}
+
+func (t *sshTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = true
+	if t.session != nil {
+		_ = t.session.Close()
+		t.session = nil
+	}
+	var errs []error
+	for i := len(t.clients) - 1; i >= 0; i-- {
+		if err := t.clients[i].Close(); !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+	t.clients = nil
+	return errors.Join(errs...)
+}
+
+func (t *sshTransport) IsAlive() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.session != nil
+}
+
+func (t *sshTransport) Read(n int) ([]byte, error) {
+	b := make([]byte, n)
+	n, err := t.reader.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	t.tapMu.Lock()
+	if t.tap != nil {
+		t.tap.Write(b[:n])
+	}
+	t.tapMu.Unlock()
+	return b[:n], nil
+}
+
+// startRaw starts collecting the bytes the device sends, untouched by the
+// driver, until takeRaw.
+func (t *sshTransport) startRaw() {
+	t.tapMu.Lock()
+	defer t.tapMu.Unlock()
+	t.tap = &bytes.Buffer{}
+}
+
+// takeRaw stops collecting and returns what was read since startRaw.
+func (t *sshTransport) takeRaw() string {
+	t.tapMu.Lock()
+	defer t.tapMu.Unlock()
+	if t.tap == nil {
+		return ""
+	}
+	raw := t.tap.String()
+	t.tap = nil
+	return raw
+}
+
+func (t *sshTransport) Write(b []byte) error {
+	_, err := t.writer.Write(b)
+	return err
+}
@@ -0,0 +1,72 @@
+package shell
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Flafl/DevOpsCore/internal/simulator"
+	"github.com/scrapli/scrapligo/transport"
+)
+
+// closeListener reports each accepted connection's Close on closed.
+type closeListener struct {
+	net.Listener
+	closed chan struct{}
+}
+
+func (l closeListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return closeConn{c, l.closed}, nil
+}
+
+type closeConn struct {
+	net.Conn
+	closed chan struct{}
+}
+
+func (c closeConn) Close() error {
+	select {
+	case c.closed <- struct{}{}:
+	default:
+	}
+	return c.Conn.Close()
+}
+
+func TestSSHTransportOpenClosesChainOnError(t *testing.T) {
+	// the simulator does not forward, so the OLT behind it is unreachable
+	jump, err := simulator.New(simulator.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := make(chan struct{}, 1)
+	go jump.Serve(closeListener{ln, closed})
+	defer jump.Close()
+
+	tr := newSSHTransport([]JumpHop{{Name: "bastion", Addr: ln.Addr().String(), Username: "u", Password: "p"}})
+	err = tr.Open(&transport.Args{Host: "192.0.2.10", Port: 22, User: "u", Password: "p", TimeoutSocket: 5 * time.Second})
+	if err == nil {
+		t.Fatal("Open succeeded through a jump host that cannot forward")
+	}
+	var hop *HopError
+	if !errors.As(err, &hop) || hop.Hop != 2 {
+		t.Fatalf("err = %v, want the OLT hop to fail", err)
+	}
+	if len(tr.clients) != 0 || !tr.closed {
+		t.Errorf("transport left open with %d clients", len(tr.clients))
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("jump host connection still open")
+	}
+}
@@ -0,0 +1,59 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+
+	"github.com/scrapli/scrapligo/driver/options"
+	"github.com/scrapli/scrapligo/transport"
+	"github.com/scrapli/scrapligo/util"
+)
+
+// loginPrompts are the in-channel login prompts a platform shows over
+// telnet.
+type loginPrompts struct {
+	Username *regexp.Regexp
+	Password *regexp.Regexp
+}
+
+// dialOptions returns the host to hand to scrapligo and the transport
+// options for the dial plan in ctx: a replay of a capture, telnet with
+// in-channel login, or SSH through any jump hosts.
+func dialOptions(ctx context.Context, host string, login loginPrompts) (string, []util.Option, error) {
+	if _, dir := captureDirs(); dir != "" {
+		path, err := latestCapture(dir, host)
+		if err != nil {
+			return "", nil, err
+		}
+		t, err := NewReplayTransport(path)
+		if err != nil {
+			return "", nil, err
+		}
+		return host, []util.Option{options.WithCustomTransport(t)}, nil
+	}
+
+	plan := dialPlanFrom(ctx)
+	if plan.Transport != "telnet" {
+		return host, []util.Option{options.WithCustomTransport(newSSHTransport(plan.Hops))}, nil
+	}
+
+	if len(plan.Hops) > 0 {
+		return "", nil, errors.New("shell: telnet through jump hosts is not supported")
+	}
+	port := 23
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		if port, err = strconv.Atoi(p); err != nil {
+			return "", nil, err
+		}
+		host = h
+	}
+	return host, []util.Option{
+		options.WithTransportType(transport.TelnetTransport),
+		options.WithPort(port),
+		options.WithUsernamePattern(login.Username),
+		options.WithPasswordPattern(login.Password),
+	}, nil
+}
@@ -0,0 +1,127 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/Flafl/DevOpsCore/internal/extractor"
+	"github.com/scrapli/scrapligo/driver/generic"
+	"github.com/scrapli/scrapligo/driver/options"
+)
+
+func init() {
+	RegisterDriver(zteC300{})
+	RegisterDriver(zteC600{}, "c600", "c620", "c650")
+}
+
+// zteC300 drives ZTE C300/C320 OLTs, whose GPON ports are named
+// gpon-olt_<shelf>/<slot>/<port>.
+type zteC300 struct{}
+
+// zteC600 drives the TITAN platform (C600/C620/C650), which renamed the
+// ports to gpon_olt-<shelf>/<slot>/<port>.
+type zteC600 struct{ zteC300 }
+
+// Prompts look like "ZXAN#" or "OLT-1(config)#".
+var ztePrompt = regexp.MustCompile(`(?m)^[\w.\-]+(?:\([\w\-]+\))?[#>]\s*$`)
+
+// ZXAN telnet asks "Username:" then "Password:".
+var zteLogin = loginPrompts{
+	Username: regexp.MustCompile(`(?im)username:\s*$`),
+	Password: regexp.MustCompile(`(?im)password:\s*$`),
+}
+
+// ZXAN prints errors as "%Error <code>: <reason>" and service failures as
+// "%Code <code>-<module> : <reason>".
+var zteErrors = []ErrorPattern{
+	{ErrResourceMissing, regexp.MustCompile(`(?mi)^\s*%\s*(?:error|code)\b.*(?:does not exist|not exist|not found|no such)`)},
+	{ErrNotPermitted, regexp.MustCompile(`(?mi)^\s*%\s*(?:error|code)\b.*(?:permission denied|access denied|privilege|not authori[sz]ed)`)},
+	{ErrSyntax, regexp.MustCompile(`(?mi)^\s*%\s*(?:error\b.*)?(?:invalid input|incomplete command|ambiguous command|unrecognized command)`)},
+}
+
+func (zteC300) Vendor() string { return "zte" }
+
+func (zteC300) ErrorPatterns() []ErrorPattern { return zteErrors }
+
+func (zteC300) InitCommands() []string {
+	return []string{"terminal length 0"}
+}
+
+func (zteC300) Connect(ctx context.Context, host, user, pass string) (*generic.Driver, error) {
+	host, dial, err := dialOptions(ctx, host, zteLogin)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := generic.NewDriver(host, append(dial,
+		options.WithAuthUsername(user),
+		options.WithAuthPassword(pass),
+		options.WithPromptPattern(ztePrompt),
+		options.WithTimeoutOps(CommandTimeout("")),
+		options.WithTermWidth(511),
+	)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := openContext(ctx, driver); err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+func (zteC300) BackupCommands() []string {
+	return []string{"show running-config"}
+}
+
+// OpticsCommands lists the cards; FollowUp then asks each GPON port.
+func (zteC300) OpticsCommands() []string {
+	return []string{"show card"}
+}
+
+// StatusCommands reads ONU names and descriptions from the running config,
+// the only place ZXAN shows them for a whole shelf at once.
+func (zteC300) StatusCommands() []string {
+	return []string{"show running-config"}
+}
+
+// HealthCommands returns CPU load, uptime and temperature, in that order.
+func (zteC300) HealthCommands() []string {
+	return []string{
+		"show processor",
+		"show system-group",
+		"show temperature",
+	}
+}
+
+// PortProtectionCommands is empty: type B protection is not collected from
+// ZTE yet.
+func (zteC300) PortProtectionCommands() []string {
+	return nil
+}
+
+func (zteC300) FollowUp(cmd, output string) []string {
+	return zteOpticsFollowUp(cmd, output, "gpon-olt_")
+}
+
+func (zteC600) FollowUp(cmd, output string) []string {
+	return zteOpticsFollowUp(cmd, output, "gpon_olt-")
+}
+
+// zteOpticsFollowUp turns the GPON cards of "show card" into one power
+// command per port.
+func zteOpticsFollowUp(cmd, output, portPrefix string) []string {
+	if cmd != "show card" {
+		return nil
+	}
+	var out []string
+	for _, c := range extractor.ExtractZteCards(output) {
+		if !c.GPON() || !c.InService() {
+			continue
+		}
+		for p := 1; p <= c.Ports; p++ {
+			out = append(out, fmt.Sprintf("show pon power attenuation %s%d/%d/%d", portPrefix, c.Shelf, c.Slot, p))
+		}
+	}
+	return out
+}
@@ -0,0 +1,336 @@
+// Package simulator is a fake Nokia ISAM SSH server for development and
+// integration tests. It answers CLI commands from fixture files and can be
+// told to respond slowly, drop connections or emit garbage.
+package simulator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	mrand "math/rand/v2"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+//go:embed fixtures/*.txt
+var builtinFixtures embed.FS
+
+// Config describes one simulated OLT.
+type Config struct {
+	// Hostname is shown in the prompt, e.g. "typ:isadmin>#".
+	Hostname string
+	// User and Password are required to log in; empty accepts anything.
+	User     string
+	Password string
+	// FixtureDir overrides the built-in fixtures. A command is answered
+	// from "<words-joined-by-dashes>.txt", e.g. show-port-protection.txt.
+	FixtureDir string
+	// BackupFile, when set, answers "info configure flat", e.g. with a
+	// capture from cmd/api/backups.
+	BackupFile string
+	// HostKey is the server key; a fresh ed25519 key is used when nil.
+	HostKey ssh.Signer
+
+	// Delay is added before every command output.
+	Delay time.Duration
+	// DisconnectRate is the probability (0..1) that a command drops the
+	// connection halfway through its output.
+	DisconnectRate float64
+	// GarbageRate is the probability (0..1) that a command answers with
+	// random bytes instead of its fixture.
+	GarbageRate float64
+}
+
+// Server is a running simulated OLT.
+type Server struct {
+	cfg    Config
+	ssh    *ssh.ServerConfig
+	prompt string
+
+	mu    sync.Mutex
+	ln    net.Listener
+	conns map[net.Conn]struct{}
+}
+
+// New prepares a server for cfg.
+func New(cfg Config) (*Server, error) {
+	if cfg.Hostname == "" {
+		cfg.Hostname = "isadmin"
+	}
+	if cfg.HostKey == nil {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.NewSignerFromKey(priv)
+		if err != nil {
+			return nil, err
+		}
+		cfg.HostKey = signer
+	}
+
+	s := &Server{
+		cfg:    cfg,
+		prompt: "typ:" + cfg.Hostname + ">#",
+		conns:  map[net.Conn]struct{}{},
+	}
+
+	checkPassword := func(user, pass string) error {
+		if cfg.User != "" && (user != cfg.User || pass != cfg.Password) {
+			return errors.New("login incorrect")
+		}
+		return nil
+	}
+	s.ssh = &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			return nil, checkPassword(c.User(), string(pass))
+		},
+		KeyboardInteractiveCallback: func(c ssh.ConnMetadata, chal ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			answers, err := chal(c.User(), "", []string{"password: "}, []bool{false})
+			if err != nil {
+				return nil, err
+			}
+			if len(answers) != 1 {
+				return nil, errors.New("login incorrect")
+			}
+			return nil, checkPassword(c.User(), answers[0])
+		},
+	}
+	s.ssh.AddHostKey(cfg.HostKey)
+	return s, nil
+}
+
+// HostKey returns the public key the server presents.
+func (s *Server) HostKey() ssh.PublicKey {
+	return s.cfg.HostKey.PublicKey()
+}
+
+// ListenAndServe listens on addr and serves until Close.
+func (s *Server) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve accepts connections on ln until Close.
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	s.ln = ln
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// Addr returns the listening address, or nil before Serve.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Addr()
+}
+
+// Close stops listening and drops every open session.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Close()
+}
+
+func (s *Server) track(c net.Conn, add bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if add {
+		s.conns[c] = struct{}{}
+	} else {
+		delete(s.conns, c)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	s.track(conn, true)
+	defer s.track(conn, false)
+	defer conn.Close()
+
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, s.ssh)
+	if err != nil {
+		return
+	}
+	defer sconn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	for nc := range chans {
+		if nc.ChannelType() != "session" {
+			_ = nc.Reject(ssh.UnknownChannelType, "only session channels are supported")
+			continue
+		}
+		ch, chReqs, err := nc.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range chReqs {
+				switch req.Type {
+				case "pty-req", "shell", "window-change", "env":
+					_ = req.Reply(true, nil)
+				default:
+					_ = req.Reply(false, nil)
+				}
+			}
+		}()
+		s.cli(ch, conn)
+		return
+	}
+}
+
+// cli runs the interactive shell: it echoes input like a PTY and answers
+// each line after the prompt.
+func (s *Server) cli(ch ssh.Channel, conn net.Conn) {
+	defer ch.Close()
+
+	write := func(b string) bool {
+		_, err := io.WriteString(ch, b)
+		return err == nil
+	}
+	if !write("\r\n" + s.prompt + " ") {
+		return
+	}
+
+	var line bytes.Buffer
+	buf := make([]byte, 1024)
+	lastCR := false
+	for {
+		n, err := ch.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, c := range buf[:n] {
+			if c == '\n' && lastCR {
+				lastCR = false
+				continue
+			}
+			lastCR = c == '\r'
+			if c != '\r' && c != '\n' {
+				line.WriteByte(c)
+				if !write(string(c)) {
+					return
+				}
+				continue
+			}
+
+			cmd := strings.TrimSpace(line.String())
+			line.Reset()
+			if cmd == "logout" || cmd == "exit" {
+				_ = write("\r\n")
+				return
+			}
+			if !s.answer(ch, conn, cmd) {
+				return
+			}
+		}
+	}
+}
+
+// answer writes the output of cmd followed by a new prompt. It returns false
+// once the connection is gone.
+func (s *Server) answer(ch ssh.Channel, conn net.Conn, cmd string) bool {
+	out := ""
+	if cmd != "" {
+		if s.cfg.Delay > 0 {
+			time.Sleep(s.cfg.Delay)
+		}
+		out = s.output(cmd)
+		if chance(s.cfg.GarbageRate) {
+			out = garbage(len(out) + 64)
+		}
+	}
+	out = strings.ReplaceAll(strings.TrimRight(out, "\n"), "\n", "\r\n")
+
+	if cmd != "" && chance(s.cfg.DisconnectRate) {
+		_, _ = io.WriteString(ch, "\r\n"+out[:len(out)/2])
+		_ = conn.Close()
+		return false
+	}
+
+	msg := "\r\n"
+	if out != "" {
+		msg += out + "\r\n"
+	}
+	_, err := io.WriteString(ch, msg+"\r\n"+s.prompt+" ")
+	return err == nil
+}
+
+// output looks cmd up in the fixtures. Unknown commands, and commands that
+// would name a file outside the fixtures, get the ISAM "invalid token" error.
+func (s *Server) output(cmd string) string {
+	name := strings.Join(strings.Fields(cmd), "-") + ".txt"
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return invalidToken(cmd)
+	}
+
+	if name == "info-configure-flat.txt" && s.cfg.BackupFile != "" {
+		b, err := os.ReadFile(s.cfg.BackupFile)
+		if err == nil {
+			return string(b)
+		}
+		log.Printf("simulator: backup fixture: %v", err)
+	}
+	if s.cfg.FixtureDir != "" {
+		if b, err := os.ReadFile(filepath.Join(s.cfg.FixtureDir, name)); err == nil {
+			return string(b)
+		}
+	}
+	if b, err := fs.ReadFile(builtinFixtures, "fixtures/"+name); err == nil {
+		return string(b)
+	}
+	if strings.HasPrefix(cmd, "environment ") {
+		return ""
+	}
+	return invalidToken(cmd)
+}
+
+func invalidToken(cmd string) string {
+	return fmt.Sprintf("%s\n^\nError : invalid token", cmd)
+}
+
+func chance(p float64) bool {
+	return p > 0 && mrand.Float64() < p
+}
+
+func garbage(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(mrand.IntN(256))
+	}
+	return string(b)
+}
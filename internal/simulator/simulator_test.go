@@ -0,0 +1,42 @@
+package simulator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputStaysInFixtures(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "fixtures")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "show-vlan.txt"), []byte("vlan table"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(Config{FixtureDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.output("show  vlan"); got != "vlan table" {
+		t.Errorf("show vlan = %q, want the fixture", got)
+	}
+	if got := s.output("show port-protection"); !strings.Contains(got, "port-protection") || strings.Contains(got, "invalid token") {
+		t.Errorf("show port-protection = %q, want the built-in fixture", got)
+	}
+	for _, cmd := range []string{"../secret", "..", "show ../../secret", `..\secret`, "fixtures/../../secret"} {
+		got := s.output(cmd)
+		if strings.Contains(got, "secret") && !strings.Contains(got, "invalid token") {
+			t.Errorf("%q read outside the fixtures: %q", cmd, got)
+		}
+		if !strings.HasSuffix(got, "Error : invalid token") {
+			t.Errorf("%q = %q, want the invalid token error", cmd, got)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package simulator
+
+import (
+	"errors"
+	"log"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// SNMPAgent is a minimal SNMPv2c agent answering GET, GETNEXT and GETBULK
+// from a fixed table, standing in for an ISAM's agent when exercising the
+// SNMP health poller.
+type SNMPAgent struct {
+	community string
+	started   time.Time
+	values    map[string]gosnmp.SnmpPDU
+	oids      []string // sorted
+
+	mu   sync.Mutex
+	conn net.PacketConn
+}
+
+// NewSNMPAgent builds an agent answering community (empty accepts any)
+// with a two-NT, two-LT ISAM shelf.
+func NewSNMPAgent(community string) *SNMPAgent {
+	a := &SNMPAgent{
+		community: community,
+		started:   time.Now(),
+		values:    map[string]gosnmp.SnmpPDU{},
+	}
+
+	integer := func(oid string, v int) { a.set(oid, gosnmp.Integer, v) }
+	str := func(oid string, v string) { a.set(oid, gosnmp.OctetString, []byte(v)) }
+
+	// SYSTEM-MIB cpuLoadAverage, by slot
+	integer("1.3.6.1.4.1.637.61.1.9.29.1.1.4.4353", 12)
+	integer("1.3.6.1.4.1.637.61.1.9.29.1.1.4.4354", 9)
+	// ASAM-EQUIP-MIB eqptBoardActualType and eqptBoardOperStatus, by slot
+	for slot, board := range map[string]string{"4353": "FANT-F", "4354": "FANT-F", "4355": "FGLT-B", "4356": "FGLT-B"} {
+		str("1.3.6.1.4.1.637.61.1.23.3.1.3."+slot, board)
+		integer("1.3.6.1.4.1.637.61.1.23.3.1.8."+slot, 1)
+	}
+	integer("1.3.6.1.4.1.637.61.1.23.3.1.8.4356", 2)
+	// eqptBoardThermalSensor actual, TCA high and shutdown high, by slot.sensor
+	for _, idx := range []string{"4353.1", "4354.1", "4355.1", "4355.2"} {
+		integer("1.3.6.1.4.1.637.61.1.23.10.1.2."+idx, 48)
+		integer("1.3.6.1.4.1.637.61.1.23.10.1.4."+idx, 75)
+		integer("1.3.6.1.4.1.637.61.1.23.10.1.6."+idx, 85)
+	}
+
+	for oid := range a.values {
+		a.oids = append(a.oids, oid)
+	}
+	slices.SortFunc(a.oids, compareOID)
+	return a
+}
+
+func (a *SNMPAgent) set(oid string, t gosnmp.Asn1BER, v any) {
+	a.values[oid] = gosnmp.SnmpPDU{Name: "." + oid, Type: t, Value: v}
+}
+
+// Serve answers requests on conn until Close.
+func (a *SNMPAgent) Serve(conn net.PacketConn) error {
+	a.mu.Lock()
+	a.conn = conn
+	a.mu.Unlock()
+
+	dec := &gosnmp.GoSNMP{}
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		req, err := dec.SnmpDecodePacket(buf[:n])
+		if err != nil {
+			continue
+		}
+		if req.Version != gosnmp.Version2c || (a.community != "" && req.Community != a.community) {
+			continue
+		}
+		resp := &gosnmp.SnmpPacket{
+			Version:   req.Version,
+			Community: req.Community,
+			PDUType:   gosnmp.GetResponse,
+			RequestID: req.RequestID,
+			Variables: a.answer(req),
+		}
+		b, err := resp.MarshalMsg()
+		if err != nil {
+			log.Printf("simulator: snmp: %v", err)
+			continue
+		}
+		_, _ = conn.WriteTo(b, addr)
+	}
+}
+
+// Close stops serving.
+func (a *SNMPAgent) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.conn == nil {
+		return nil
+	}
+	return a.conn.Close()
+}
+
+func (a *SNMPAgent) answer(req *gosnmp.SnmpPacket) []gosnmp.SnmpPDU {
+	var out []gosnmp.SnmpPDU
+	switch req.PDUType {
+	case gosnmp.GetRequest:
+		for _, v := range req.Variables {
+			out = append(out, a.get(trimOID(v.Name)))
+		}
+	case gosnmp.GetNextRequest:
+		for _, v := range req.Variables {
+			out = append(out, a.next(trimOID(v.Name)))
+		}
+	case gosnmp.GetBulkRequest:
+		nonRep := min(int(req.NonRepeaters), len(req.Variables))
+		for _, v := range req.Variables[:nonRep] {
+			out = append(out, a.next(trimOID(v.Name)))
+		}
+		cursors := make([]string, 0, len(req.Variables)-nonRep)
+		for _, v := range req.Variables[nonRep:] {
+			cursors = append(cursors, trimOID(v.Name))
+		}
+		for r := 0; r < int(req.MaxRepetitions) && len(cursors) > 0; r++ {
+			for i, c := range cursors {
+				pdu := a.next(c)
+				out = append(out, pdu)
+				cursors[i] = trimOID(pdu.Name)
+			}
+			if out[len(out)-1].Type == gosnmp.EndOfMibView {
+				break
+			}
+		}
+	}
+	return out
+}
+
+func (a *SNMPAgent) get(oid string) gosnmp.SnmpPDU {
+	if oid == "1.3.6.1.2.1.1.3.0" {
+		ticks := uint32(time.Since(a.started) / (10 * time.Millisecond))
+		return gosnmp.SnmpPDU{Name: "." + oid, Type: gosnmp.TimeTicks, Value: ticks}
+	}
+	if pdu, ok := a.values[oid]; ok {
+		return pdu
+	}
+	return gosnmp.SnmpPDU{Name: "." + oid, Type: gosnmp.NoSuchObject}
+}
+
+func (a *SNMPAgent) next(oid string) gosnmp.SnmpPDU {
+	i, found := slices.BinarySearchFunc(a.oids, oid, compareOID)
+	if found {
+		i++
+	}
+	if i >= len(a.oids) {
+		return gosnmp.SnmpPDU{Name: "." + oid, Type: gosnmp.EndOfMibView}
+	}
+	return a.values[a.oids[i]]
+}
+
+func trimOID(oid string) string {
+	return strings.TrimPrefix(oid, ".")
+}
+
+func compareOID(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			return x - y
+		}
+	}
+	return len(as) - len(bs)
+}
@@ -0,0 +1,32 @@
+package snmp
+
+// Standard MIB objects, answered by most agents.
+const (
+	oidSysUpTime = "1.3.6.1.2.1.1.3.0" // SNMPv2-MIB sysUpTime, in hundredths of a second
+
+	oidHrProcessorLoad = "1.3.6.1.2.1.25.3.3.1.2" // HOST-RESOURCES-MIB hrProcessorLoad
+
+	oidEntPhysicalName  = "1.3.6.1.2.1.47.1.1.1.1.7" // ENTITY-MIB entPhysicalName
+	oidEntPhysicalClass = "1.3.6.1.2.1.47.1.1.1.1.5" // ENTITY-MIB entPhysicalClass
+	oidEntSensorType    = "1.3.6.1.2.1.99.1.1.1.1"   // ENTITY-SENSOR-MIB entPhySensorType
+	oidEntSensorValue   = "1.3.6.1.2.1.99.1.1.1.4"   // ENTITY-SENSOR-MIB entPhySensorValue
+	oidEntStateOper     = "1.3.6.1.2.1.131.1.1.1.3"  // ENTITY-STATE-MIB entStateOper
+	entClassModule      = 9                          // entPhysicalClass module(9)
+	entSensorCelsius    = 8                          // entPhySensorType celsius(8)
+)
+
+// Nokia ISAM (ASAM) MIB objects. Tables are indexed by the equipment slot.
+const (
+	oidIsamCpuLoad        = "1.3.6.1.4.1.637.61.1.9.29.1.1.4" // SYSTEM-MIB cpuLoadAverage
+	oidIsamBoardType      = "1.3.6.1.4.1.637.61.1.23.3.1.3"   // ASAM-EQUIP-MIB eqptBoardActualType
+	oidIsamBoardOper      = "1.3.6.1.4.1.637.61.1.23.3.1.8"   // ASAM-EQUIP-MIB eqptBoardOperStatus
+	oidIsamSensorActual   = "1.3.6.1.4.1.637.61.1.23.10.1.2"  // ASAM-EQUIP-MIB eqptBoardThermalSensorActualTemperature
+	oidIsamSensorTcaHigh  = "1.3.6.1.4.1.637.61.1.23.10.1.4"  // ASAM-EQUIP-MIB eqptBoardThermalSensorTcaThresholdHigh
+	oidIsamSensorShutHigh = "1.3.6.1.4.1.637.61.1.23.10.1.6"  // ASAM-EQUIP-MIB eqptBoardThermalSensorShutdownThresholdHigh
+)
+
+// entStateOper and eqptBoardOperStatus values.
+var (
+	entOperStatus  = map[int64]string{1: "unknown", 2: "disabled", 3: "enabled", 4: "testing"}
+	isamOperStatus = map[int64]string{1: "enabled", 2: "disabled"}
+)
@@ -0,0 +1,200 @@
+package snmp
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Flafl/DevOpsCore/internal/extractor"
+	"github.com/gosnmp/gosnmp"
+)
+
+// walker walks the tables of one session. A table the agent does not have
+// simply comes back empty; the first transport error is kept in err and
+// stops every later walk.
+type walker struct {
+	g   *gosnmp.GoSNMP
+	err error
+}
+
+// table returns the columns of root keyed by row index (the OID suffix
+// after root, without the leading dot).
+func (w *walker) table(root string) map[string]gosnmp.SnmpPDU {
+	if w.err != nil {
+		return nil
+	}
+	walk := w.g.BulkWalkAll
+	if w.g.Version == gosnmp.Version1 {
+		walk = w.g.WalkAll
+	}
+	pdus, err := walk(root)
+	if err != nil {
+		w.err = err
+		return nil
+	}
+	out := make(map[string]gosnmp.SnmpPDU, len(pdus))
+	for _, pdu := range pdus {
+		switch pdu.Type {
+		case gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+			continue
+		}
+		idx := strings.TrimPrefix(strings.TrimPrefix(pdu.Name, "."), root+".")
+		out[idx] = pdu
+	}
+	return out
+}
+
+func (w *walker) isamCpu() []extractor.CpuLoad {
+	loads := w.table(oidIsamCpuLoad)
+	var out []extractor.CpuLoad
+	for _, idx := range keys(loads) {
+		out = append(out, extractor.CpuLoad{Slot: idx, Average: toInt(loads[idx])})
+	}
+	return out
+}
+
+func (w *walker) isamTemperatures() []extractor.Temperature {
+	actual := w.table(oidIsamSensorActual)
+	if len(actual) == 0 {
+		return nil
+	}
+	tca := w.table(oidIsamSensorTcaHigh)
+	shut := w.table(oidIsamSensorShutHigh)
+
+	var out []extractor.Temperature
+	for _, idx := range keys(actual) {
+		// rows are indexed slot.sensor
+		slot, sensor := idx, "0"
+		if i := strings.LastIndexByte(idx, '.'); i >= 0 {
+			slot, sensor = idx[:i], idx[i+1:]
+		}
+		out = append(out, extractor.Temperature{
+			Slot:     slot,
+			SensorID: atoi(sensor),
+			ActTemp:  toInt(actual[idx]),
+			TcaHigh:  toInt(tca[idx]),
+			ShutHigh: toInt(shut[idx]),
+		})
+	}
+	return out
+}
+
+func (w *walker) isamBoards() []Board {
+	oper := w.table(oidIsamBoardOper)
+	if len(oper) == 0 {
+		return nil
+	}
+	types := w.table(oidIsamBoardType)
+
+	var out []Board
+	for _, idx := range keys(oper) {
+		out = append(out, Board{
+			Slot:       idx,
+			Name:       toString(types[idx]),
+			OperStatus: status(isamOperStatus, oper[idx]),
+		})
+	}
+	return out
+}
+
+func (w *walker) hrCpu() []extractor.CpuLoad {
+	loads := w.table(oidHrProcessorLoad)
+	var out []extractor.CpuLoad
+	for _, idx := range keys(loads) {
+		out = append(out, extractor.CpuLoad{Slot: idx, Average: toInt(loads[idx])})
+	}
+	return out
+}
+
+func (w *walker) entityTemperatures() []extractor.Temperature {
+	types := w.table(oidEntSensorType)
+	if len(types) == 0 {
+		return nil
+	}
+	values := w.table(oidEntSensorValue)
+	names := w.table(oidEntPhysicalName)
+
+	var out []extractor.Temperature
+	for _, idx := range keys(types) {
+		v, ok := values[idx]
+		if toInt(types[idx]) != entSensorCelsius || !ok {
+			continue
+		}
+		slot := toString(names[idx])
+		if slot == "" {
+			slot = idx
+		}
+		out = append(out, extractor.Temperature{Slot: slot, SensorID: atoi(idx), ActTemp: toInt(v)})
+	}
+	return out
+}
+
+func (w *walker) entityBoards() []Board {
+	classes := w.table(oidEntPhysicalClass)
+	if len(classes) == 0 {
+		return nil
+	}
+	names := w.table(oidEntPhysicalName)
+	oper := w.table(oidEntStateOper)
+
+	var out []Board
+	for _, idx := range keys(classes) {
+		if toInt(classes[idx]) != entClassModule {
+			continue
+		}
+		b := Board{Slot: idx, Name: toString(names[idx]), OperStatus: "unknown"}
+		if pdu, ok := oper[idx]; ok {
+			b.OperStatus = status(entOperStatus, pdu)
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// keys returns the row indexes of t in OID order.
+func keys(t map[string]gosnmp.SnmpPDU) []string {
+	out := make([]string, 0, len(t))
+	for k := range t {
+		out = append(out, k)
+	}
+	slices.SortFunc(out, compareOID)
+	return out
+}
+
+func compareOID(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := atoi(as[i]) - atoi(bs[i]); c != 0 {
+			return c
+		}
+	}
+	return len(as) - len(bs)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// toInt reads an integer PDU; missing rows read as 0.
+func toInt(pdu gosnmp.SnmpPDU) int {
+	if pdu.Value == nil {
+		return 0
+	}
+	return int(gosnmp.ToBigInt(pdu.Value).Int64())
+}
+
+func toString(pdu gosnmp.SnmpPDU) string {
+	if b, ok := pdu.Value.([]byte); ok {
+		return strings.TrimSpace(string(b))
+	}
+	return ""
+}
+
+func status(names map[int64]string, pdu gosnmp.SnmpPDU) string {
+	v := int64(toInt(pdu))
+	if s, ok := names[v]; ok {
+		return s
+	}
+	return strconv.FormatInt(v, 10)
+}